@@ -7,7 +7,11 @@ import (
 	"github.com/faisal-amiruddin/YouDo/pkg/config"
 	"github.com/faisal-amiruddin/YouDo/pkg/database"
 	"github.com/faisal-amiruddin/YouDo/pkg/handler"
+	"github.com/faisal-amiruddin/YouDo/pkg/mailer"
 	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/notifier"
+	"github.com/faisal-amiruddin/YouDo/pkg/oidc"
 	"github.com/faisal-amiruddin/YouDo/pkg/repository"
 	"github.com/faisal-amiruddin/YouDo/pkg/service"
 	"github.com/faisal-amiruddin/YouDo/pkg/utils"
@@ -34,17 +38,89 @@ func initRouter() {
 	}
 
 	db, _ := database.Connect(&cfg.Database)
+	mailSender, _ := mailer.New(&cfg.Mail)
 
 	userRepo := repository.NewUserRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	resetRepo := repository.NewPasswordResetRepository(db)
+	verifyRepo := repository.NewEmailVerificationRepository(db)
+	personalTokenRepo := repository.NewPersonalTokenRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	loginFailureRepo := repository.NewLoginFailureRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	taskEventRepo := repository.NewTaskEventRepository(db)
+	statusRepo := repository.NewTaskStatusRepository(db)
+	dependencyRepo := repository.NewTaskDependencyRepository(db)
+	shareRepo := repository.NewShareRepository(db)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
 
-	authService := service.NewAuthService(userRepo, cfg.JWT.Secret, "24h")
-	taskService := service.NewTaskService(taskRepo)
+	twoFactorService := service.NewTwoFactorService(userRepo, twoFactorRepo)
+	loginThrottle := service.NewLoginThrottle(loginFailureRepo, auditRepo, service.LoginThrottleConfig{
+		MaxFailures:     cfg.Security.LoginMaxFailures,
+		IPMaxFailures:   cfg.Security.LoginIPMaxFailures,
+		FailureWindow:   cfg.Security.LoginFailureWindow,
+		BackoffBase:     cfg.Security.LoginBackoffBase,
+		BackoffMax:      cfg.Security.LoginBackoffMax,
+		LockoutDuration: cfg.Security.LoginLockoutDuration,
+	})
+
+	authService := service.NewAuthService(userRepo, tokenRepo, resetRepo, verifyRepo, twoFactorService, loginThrottle, mailSender, service.AuthConfig{
+		JWTSecret:                cfg.JWT.Secret,
+		JWTExpiry:                cfg.JWT.Expiry,
+		RefreshExpiry:            cfg.JWT.RefreshExpiry,
+		PasswordResetExpiry:      cfg.Security.PasswordResetExpiry,
+		EmailVerificationExpiry:  cfg.Security.EmailVerificationExpiry,
+		TwoFactorChallengeExpiry: cfg.Security.TwoFactorChallengeExpiry,
+		FrontendURL:              cfg.Server.FrontendURL,
+		PublicURL:                cfg.Server.PublicURL,
+	})
+	accessService := service.NewAccessService(shareRepo, userRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+	taskNotifier := notifier.NewInAppNotifier(notificationService)
+	taskService := service.NewTaskService(
+		taskRepo, tagRepo, projectRepo, taskEventRepo, statusRepo, dependencyRepo, commentRepo, userRepo,
+		accessService, taskNotifier,
+	)
+	commentService := service.NewCommentService(commentRepo, taskRepo, userRepo, accessService, taskNotifier)
+	workspaceService := service.NewWorkspaceService(workspaceRepo, userRepo, mailSender, service.WorkspaceConfig{
+		InvitationExpiry: cfg.Security.WorkspaceInvitationExpiry,
+		FrontendURL:      cfg.Server.FrontendURL,
+	})
+	tagService := service.NewTagService(tagRepo)
+	projectService := service.NewProjectService(projectRepo, accessService)
+	statusService := service.NewTaskStatusService(statusRepo)
+	// Reminders are delivered and the trash is purged by the background jobs
+	// of a long-running instance (cmd/api); serverless functions do not live
+	// long enough to run them.
+	reminderService := service.NewReminderService(reminderRepo, taskRepo, accessService)
+	userService := service.NewUserService(userRepo, tokenRepo, authService, twoFactorService)
+	personalTokenService := service.NewPersonalTokenService(personalTokenRepo, userRepo)
+	oidcService := service.NewOIDCService(oidc.NewProviders(&cfg.OIDC), identityRepo, userRepo, authService, cfg.OIDC.StateExpiry)
 
 	authHandler := handler.NewAuthHandler(authService)
 	taskHandler := handler.NewTaskHandler(taskService)
+	tagHandler := handler.NewTagHandler(tagService)
+	projectHandler := handler.NewProjectHandler(projectService)
+	statusHandler := handler.NewTaskStatusHandler(statusService)
+	shareHandler := handler.NewShareHandler(accessService)
+	workspaceHandler := handler.NewWorkspaceHandler(workspaceService)
+	reminderHandler := handler.NewReminderHandler(reminderService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	userHandler := handler.NewUserHandler(userService)
+	personalTokenHandler := handler.NewPersonalTokenHandler(personalTokenService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
+	oidcHandler := handler.NewOIDCHandler(oidcService)
 
 	router = gin.New()
+	router.SetTrustedProxies(cfg.Security.TrustedProxies)
 
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logger())
@@ -66,22 +142,158 @@ func initRouter() {
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	authMiddleware := middleware.AuthMiddleware(cfg.JWT.Secret, tokenRepo, personalTokenService, workspaceService)
+
 	api := router.Group("/api")
 
 	auth := api.Group("/auth")
 	{
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
+		auth.POST("/login/2fa", authHandler.LoginTwoFactor)
+		auth.POST("/refresh", authHandler.Refresh)
+		auth.POST("/logout", authHandler.Logout)
+		auth.POST("/forgot-password", authHandler.ForgotPassword)
+		auth.POST("/reset-password", authHandler.ResetPassword)
+		auth.GET("/verify", authHandler.VerifyEmail)
+		auth.POST("/verify/resend", authMiddleware, authHandler.ResendVerification)
+		auth.GET("/oidc/:provider/login", oidcHandler.Login)
+		auth.GET("/oidc/:provider/callback", oidcHandler.Callback)
+	}
+
+	users := api.Group("/users")
+	users.Use(authMiddleware, middleware.RequireSession())
+	{
+		users.GET("/me", userHandler.GetProfile)
+		users.PATCH("/me", userHandler.UpdateProfile)
+		users.DELETE("/me", userHandler.DeleteAccount)
+		users.GET("/me/tokens", personalTokenHandler.GetTokens)
+		users.POST("/me/tokens", personalTokenHandler.CreateToken)
+		users.DELETE("/me/tokens/:id", personalTokenHandler.DeleteToken)
+		users.POST("/me/2fa/enroll", twoFactorHandler.Enroll)
+		users.POST("/me/2fa/confirm", twoFactorHandler.Confirm)
+		users.POST("/me/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+		users.DELETE("/me/2fa", twoFactorHandler.Disable)
+		users.GET("/me/identities", oidcHandler.ListIdentities)
+		users.POST("/me/identities/:provider", oidcHandler.LinkIdentity)
+		users.DELETE("/me/identities/:provider", oidcHandler.UnlinkIdentity)
+		users.POST("/me/identities/:provider/reauth", oidcHandler.Reauthenticate)
 	}
 
 	tasks := api.Group("/tasks")
-	tasks.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+	tasks.Use(
+		authMiddleware,
+		middleware.RequireVerifiedEmail(cfg.Security.EmailVerification),
+		middleware.RequireScope(model.ScopeTasksRead, model.ScopeTasksWrite),
+	)
 	{
 		tasks.POST("", taskHandler.CreateTask)
 		tasks.GET("", taskHandler.GetAllTasks)
+		tasks.GET("/board", taskHandler.GetBoard)
+		tasks.GET("/trash", taskHandler.GetTrash)
+		tasks.DELETE("/trash", taskHandler.EmptyTrash)
+		tasks.POST("/bulk", taskHandler.BulkUpdateTasks)
 		tasks.GET("/:id", taskHandler.GetTask)
 		tasks.PUT("/:id", taskHandler.UpdateTask)
 		tasks.DELETE("/:id", taskHandler.DeleteTask)
+		tasks.POST("/:id/reorder", taskHandler.MoveTask)
+		tasks.POST("/:id/restore", taskHandler.RestoreTask)
+		tasks.DELETE("/:id/purge", taskHandler.PurgeTask)
+		tasks.GET("/:id/history", taskHandler.GetTaskHistory)
+		tasks.GET("/:id/subtasks", taskHandler.GetSubtasks)
+		tasks.POST("/:id/subtasks", taskHandler.CreateSubtask)
+		tasks.POST("/:id/blockers", taskHandler.AddBlocker)
+		tasks.DELETE("/:id/blockers/:blockerId", taskHandler.RemoveBlocker)
+		tasks.PUT("/:id/assignee", taskHandler.AssignTask)
+		tasks.DELETE("/:id/assignee", taskHandler.UnassignTask)
+		tasks.GET("/:id/shares", shareHandler.GetTaskShares)
+		tasks.POST("/:id/shares", shareHandler.ShareTask)
+		tasks.DELETE("/:id/shares/:userId", shareHandler.UnshareTask)
+		tasks.GET("/:id/reminders", reminderHandler.GetReminders)
+		tasks.POST("/:id/reminders", reminderHandler.CreateReminder)
+		tasks.DELETE("/:id/reminders/:reminderId", reminderHandler.DeleteReminder)
+		tasks.GET("/:id/comments", commentHandler.GetComments)
+		tasks.POST("/:id/comments", commentHandler.CreateComment)
+		tasks.PATCH("/:id/comments/:commentId", commentHandler.UpdateComment)
+		tasks.DELETE("/:id/comments/:commentId", commentHandler.DeleteComment)
+	}
+
+	tags := api.Group("/tags")
+	tags.Use(
+		authMiddleware,
+		middleware.RequireVerifiedEmail(cfg.Security.EmailVerification),
+		middleware.RequireScope(model.ScopeTasksRead, model.ScopeTasksWrite),
+	)
+	{
+		tags.POST("", tagHandler.CreateTag)
+		tags.GET("", tagHandler.GetTags)
+		tags.PATCH("/:id", tagHandler.UpdateTag)
+		tags.DELETE("/:id", tagHandler.DeleteTag)
+	}
+
+	projects := api.Group("/projects")
+	projects.Use(
+		authMiddleware,
+		middleware.RequireVerifiedEmail(cfg.Security.EmailVerification),
+		middleware.RequireScope(model.ScopeTasksRead, model.ScopeTasksWrite),
+	)
+	{
+		projects.POST("", projectHandler.CreateProject)
+		projects.GET("", projectHandler.GetProjects)
+		projects.GET("/:id", projectHandler.GetProject)
+		projects.PATCH("/:id", projectHandler.UpdateProject)
+		projects.DELETE("/:id", projectHandler.DeleteProject)
+		projects.GET("/:id/shares", shareHandler.GetProjectShares)
+		projects.POST("/:id/shares", shareHandler.ShareProject)
+		projects.DELETE("/:id/shares/:userId", shareHandler.UnshareProject)
+	}
+
+	workspaces := api.Group("/workspaces")
+	workspaces.Use(
+		authMiddleware,
+		middleware.RequireVerifiedEmail(cfg.Security.EmailVerification),
+		middleware.RequireScope(model.ScopeWorkspacesRead, model.ScopeWorkspacesWrite),
+	)
+	{
+		workspaces.POST("", workspaceHandler.CreateWorkspace)
+		workspaces.GET("", workspaceHandler.GetWorkspaces)
+		workspaces.POST("/invitations/accept", workspaceHandler.AcceptInvitation)
+		workspaces.GET("/:id", workspaceHandler.GetWorkspace)
+		workspaces.PATCH("/:id", workspaceHandler.UpdateWorkspace)
+		workspaces.DELETE("/:id", workspaceHandler.DeleteWorkspace)
+		workspaces.GET("/:id/members", workspaceHandler.GetMembers)
+		workspaces.PATCH("/:id/members/:userId", workspaceHandler.UpdateMember)
+		workspaces.DELETE("/:id/members/:userId", workspaceHandler.RemoveMember)
+		workspaces.GET("/:id/invitations", workspaceHandler.GetInvitations)
+		workspaces.POST("/:id/invitations", workspaceHandler.InviteMember)
+		workspaces.DELETE("/:id/invitations/:invitationId", workspaceHandler.DeleteInvitation)
+	}
+
+	statuses := api.Group("/statuses")
+	statuses.Use(
+		authMiddleware,
+		middleware.RequireVerifiedEmail(cfg.Security.EmailVerification),
+		middleware.RequireScope(model.ScopeStatusesRead, model.ScopeStatusesWrite),
+	)
+	{
+		statuses.POST("", statusHandler.CreateStatus)
+		statuses.GET("", statusHandler.GetStatuses)
+		statuses.PATCH("/:id", statusHandler.UpdateStatus)
+		statuses.DELETE("/:id", statusHandler.DeleteStatus)
+	}
+
+	notifications := api.Group("/notifications")
+	notifications.Use(
+		authMiddleware,
+		middleware.RequireVerifiedEmail(cfg.Security.EmailVerification),
+		middleware.RequireScope(model.ScopeNotificationsRead, model.ScopeNotificationsWrite),
+	)
+	{
+		notifications.GET("", notificationHandler.GetNotifications)
+		notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+		notifications.POST("/read-all", notificationHandler.MarkAllRead)
+		notifications.POST("/:id/read", notificationHandler.MarkRead)
+		notifications.DELETE("/:id", notificationHandler.DeleteNotification)
 	}
 }
 
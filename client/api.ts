@@ -2,6 +2,8 @@
 import { ApiResponse, AuthData, Task, TaskList, CreateTaskRequest, UpdateTaskRequest, Priority } from './types';
 
 const BASE_URL = 'https://you-do-beryl.vercel.app/api';
+// Largest page size the API accepts for task lists.
+const TASK_PAGE_SIZE = 100;
 
 const getAuthHeaders = () => {
   const token = localStorage.getItem('youdo_token');
@@ -70,10 +72,21 @@ export const api = {
     }
   },
   tasks: {
+    // The API returns tasks in pages; fetch all of them so the list is complete.
     getAll: async (): Promise<ApiResponse<TaskList>> => {
-      return fetchWithLog(`${BASE_URL}/tasks`, {
-        headers: getAuthHeaders(),
-      });
+      const tasks: Task[] = [];
+      let res: ApiResponse<TaskList>;
+      do {
+        res = await fetchWithLog(`${BASE_URL}/tasks?limit=${TASK_PAGE_SIZE}&offset=${tasks.length}`, {
+          headers: getAuthHeaders(),
+        });
+        if (!res.success) {
+          return res;
+        }
+        tasks.push(...res.data.tasks);
+      } while (res.data.tasks.length > 0 && tasks.length < res.data.total);
+
+      return { ...res, data: { ...res.data, tasks, offset: 0, limit: tasks.length } };
     },
     create: async (task: CreateTaskRequest): Promise<ApiResponse<Task>> => {
       return fetchWithLog(`${BASE_URL}/tasks`, {
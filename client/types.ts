@@ -34,6 +34,8 @@ export interface Task {
 export interface TaskList {
   tasks: Task[];
   total: number;
+  limit: number;
+  offset: number;
 }
 
 export interface CreateTaskRequest {
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_timezone;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_rule;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_rule VARCHAR(255);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
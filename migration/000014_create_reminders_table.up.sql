@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS task_reminders (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    remind_at TIMESTAMP,
+    offset_minutes INTEGER,
+    channel VARCHAR(20) NOT NULL DEFAULT 'in_app',
+    webhook_url VARCHAR(2048) NOT NULL DEFAULT '',
+    fire_at TIMESTAMP,
+    sent_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((remind_at IS NULL) <> (offset_minutes IS NULL))
+);
+
+CREATE INDEX idx_task_reminders_task_id ON task_reminders(task_id);
+CREATE INDEX idx_task_reminders_pending ON task_reminders(fire_at) WHERE sent_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS task_events_no_update ON task_events;
+DROP FUNCTION IF EXISTS task_events_append_only();
+DROP TABLE IF EXISTS task_events;
\ No newline at end of file
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS task_events (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_task_events_task_id ON task_events(task_id, created_at);
+
+-- Events cannot be changed, except that deleting an account clears the
+-- actor of its events through ON DELETE SET NULL.
+CREATE OR REPLACE FUNCTION task_events_append_only() RETURNS trigger AS $$
+BEGIN
+    IF NEW.actor_id IS NULL
+        AND (NEW.id, NEW.task_id, NEW.action, NEW.changes, NEW.created_at)
+            IS NOT DISTINCT FROM (OLD.id, OLD.task_id, OLD.action, OLD.changes, OLD.created_at) THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'task_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_events_no_update
+    BEFORE UPDATE ON task_events
+    FOR EACH ROW EXECUTE FUNCTION task_events_append_only();
@@ -0,0 +1,11 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS position BIGINT NOT NULL DEFAULT 0;
+
+UPDATE tasks
+SET position = ranked.rank * 1024
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at, id) AS rank
+    FROM tasks
+) ranked
+WHERE tasks.id = ranked.id;
+
+CREATE INDEX idx_tasks_user_position ON tasks(user_id, position);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_status_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS status_id;
+DROP TABLE IF EXISTS task_statuses;
\ No newline at end of file
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS task_statuses (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_done BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_task_statuses_user_name ON task_statuses(user_id, LOWER(name));
+
+INSERT INTO task_statuses (user_id, name, position, is_done)
+SELECT u.id, d.name, d.position, d.is_done
+FROM users u
+CROSS JOIN (VALUES
+    ('To Do', 0, false),
+    ('In Progress', 1, false),
+    ('Blocked', 2, false),
+    ('In Review', 3, false),
+    ('Done', 4, true)
+) AS d(name, position, is_done);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status_id INTEGER REFERENCES task_statuses(id);
+
+UPDATE tasks t
+SET status_id = s.id
+FROM task_statuses s
+WHERE s.user_id = t.user_id AND s.name = CASE WHEN t.is_completed THEN 'Done' ELSE 'To Do' END;
+
+ALTER TABLE tasks ALTER COLUMN status_id SET NOT NULL;
+
+CREATE INDEX idx_tasks_status_id ON tasks(status_id);
@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS workspaces (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user_id ON workspace_members(user_id);
+CREATE UNIQUE INDEX idx_workspace_members_owner ON workspace_members(workspace_id) WHERE role = 'owner';
+
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('admin', 'member')),
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_workspace_invitations_workspace_id ON workspace_invitations(workspace_id);
+
+INSERT INTO workspaces (name, created_by)
+SELECT 'Personal', id FROM users ORDER BY id;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT id, created_by, 'owner' FROM workspaces;
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS workspace_id INTEGER REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE projects p
+SET workspace_id = w.id
+FROM workspaces w
+WHERE w.created_by = p.user_id;
+
+ALTER TABLE projects ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX idx_projects_workspace_id ON projects(workspace_id);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS workspace_id INTEGER REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE tasks t
+SET workspace_id = w.id
+FROM workspaces w
+WHERE w.created_by = t.user_id;
+
+ALTER TABLE tasks ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX idx_tasks_workspace_id ON tasks(workspace_id);
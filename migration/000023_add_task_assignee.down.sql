@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_assignee_id;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS assignee_id;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_comment_mentions;
+DROP TABLE IF EXISTS task_comments;
\ No newline at end of file
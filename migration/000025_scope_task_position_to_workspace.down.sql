@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_workspace_position;
+CREATE INDEX idx_tasks_user_position ON tasks(user_id, position);
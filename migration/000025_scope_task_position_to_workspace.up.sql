@@ -0,0 +1,10 @@
+UPDATE tasks
+SET position = ranked.rank * 1024
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY workspace_id ORDER BY position, created_at, id) AS rank
+    FROM tasks
+) ranked
+WHERE tasks.id = ranked.id;
+
+DROP INDEX IF EXISTS idx_tasks_user_position;
+CREATE INDEX idx_tasks_workspace_position ON tasks(workspace_id, position);
@@ -13,6 +13,8 @@ import (
 type ServerConfig struct {
 	Port string
 	Env  string
+	FrontendURL string
+	PublicURL string
 }
 
 type DatabaseConfig struct {
@@ -27,12 +29,65 @@ type DatabaseConfig struct {
 type JWTConfig struct {
 	Secret string
 	Expiry time.Duration
+	RefreshExpiry time.Duration
+	PurgeInterval time.Duration
 }
 
 type SecurityConfig struct {
 	RateLimitRequest int
 	RateLimitDuration time.Duration
 	CORSAllowedOrigins []string
+	TrustedProxies []string
+	PasswordResetExpiry time.Duration
+	EmailVerification string
+	EmailVerificationExpiry time.Duration
+	TwoFactorChallengeExpiry time.Duration
+	WorkspaceInvitationExpiry time.Duration
+	LoginMaxFailures int
+	LoginIPMaxFailures int
+	LoginFailureWindow time.Duration
+	LoginBackoffBase time.Duration
+	LoginBackoffMax time.Duration
+	LoginLockoutDuration time.Duration
+}
+
+type MailConfig struct {
+	Driver string
+	From string
+	SMTPHost string
+	SMTPPort string
+	SMTPUsername string
+	SMTPPassword string
+	FileDir string
+}
+
+type OIDCProviderConfig struct {
+	Name string
+	Issuer string
+	ClientID string
+	ClientSecret string
+	RedirectURL string
+	Scopes []string
+}
+
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	StateExpiry time.Duration
+}
+
+type ReminderConfig struct {
+	SchedulerEnabled bool
+	PollInterval time.Duration
+	BatchSize int
+	MaxAttempts int
+	RetryDelay time.Duration
+	WebhookSecret string
+	WebhookTimeout time.Duration
+}
+
+type TrashConfig struct {
+	Retention time.Duration
+	PurgeInterval time.Duration
 }
 
 type LogConfig struct {
@@ -44,6 +99,10 @@ type Config struct {
 	Database DatabaseConfig
 	JWT JWTConfig
 	Security SecurityConfig
+	Mail MailConfig
+	OIDC OIDCConfig
+	Reminder ReminderConfig
+	Trash TrashConfig
 	Log LogConfig
 }
 
@@ -88,6 +147,30 @@ func parseSlice(value string) []string {
 	return result
 }
 
+// loadOIDCProviders reads the providers listed in OIDC_PROVIDERS. Each name
+// is configured through OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET,
+// _SCOPES and _REDIRECT_URL.
+func loadOIDCProviders(publicURL string) []OIDCProviderConfig {
+	names := parseSlice(getEnv("OIDC_PROVIDERS", ""))
+	providers := make([]OIDCProviderConfig, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+		providers = append(providers, OIDCProviderConfig{
+			Name: name,
+			Issuer: getEnv(prefix+"ISSUER", ""),
+			ClientID: getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL: getEnv(prefix+"REDIRECT_URL", publicURL+"/api/auth/oidc/"+name+"/callback"),
+			Scopes: parseSlice(getEnv(prefix+"SCOPES", "openid,email,profile")),
+		})
+	}
+
+	return providers
+}
+
 func (c *Config) Validate() error {
 	if c.JWT.Secret == "" {
 		return fmt.Errorf("JWT_SECRET is required")
@@ -97,6 +180,34 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("DB_PASSWORD is required in production!")
 	}
 
+	switch c.Security.EmailVerification {
+	case "off", "readonly", "required":
+	default:
+		return fmt.Errorf("EMAIL_VERIFICATION must be one of off, readonly or required")
+	}
+
+	if c.Security.LoginMaxFailures < 1 || c.Security.LoginIPMaxFailures < 1 {
+		return fmt.Errorf("LOGIN_MAX_FAILURES and LOGIN_IP_MAX_FAILURES must be at least 1")
+	}
+
+	if c.Reminder.PollInterval <= 0 || c.Reminder.BatchSize < 1 || c.Reminder.MaxAttempts < 1 {
+		return fmt.Errorf("REMINDER_POLL_INTERVAL, REMINDER_BATCH_SIZE and REMINDER_MAX_ATTEMPTS must be positive")
+	}
+
+	if c.JWT.PurgeInterval <= 0 {
+		return fmt.Errorf("TOKEN_PURGE_INTERVAL must be positive")
+	}
+
+	if c.Trash.Retention > 0 && c.Trash.PurgeInterval <= 0 {
+		return fmt.Errorf("TRASH_PURGE_INTERVAL must be positive")
+	}
+
+	for _, provider := range c.OIDC.Providers {
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return fmt.Errorf("OIDC provider %s needs an issuer and a client ID", provider.Name)
+		}
+	}
+
 	return nil
 }
 
@@ -107,6 +218,8 @@ func Load() (*Config, error) {
 		Server: ServerConfig{
 			Port: getEnv("PORT", "8080"),
 			Env: getEnv("ENV", "development"),
+			FrontendURL: strings.TrimRight(getEnv("FRONTEND_URL", "http://localhost:3000"), "/"),
+			PublicURL: strings.TrimRight(getEnv("PUBLIC_URL", "http://localhost:8080"), "/"),
 		},
 		Database: DatabaseConfig{
 			Host: getEnv("DB_HOST", "localhost"),
@@ -118,18 +231,59 @@ func Load() (*Config, error) {
 		},
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", ""),
-			Expiry: parseDuration(getEnv("JWT_EXPIRY", "24h"), 24*time.Hour),
+			Expiry: parseDuration(getEnv("JWT_EXPIRY", "15m"), 15*time.Minute),
+			RefreshExpiry: parseDuration(getEnv("JWT_REFRESH_EXPIRY", "720h"), 720*time.Hour),
+			PurgeInterval: parseDuration(getEnv("TOKEN_PURGE_INTERVAL", "1h"), time.Hour),
 		},
 		Security: SecurityConfig{
 			RateLimitRequest: parseInt(getEnv("RATE_LIMIT_REQUESTS", "100"), 100),
 			RateLimitDuration: parseDuration(getEnv("RATE_LIMIT_DURATION", "1m"), time.Minute),
 			CORSAllowedOrigins: parseSlice(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000")),
+			TrustedProxies: parseSlice(getEnv("TRUSTED_PROXIES", "")),
+			PasswordResetExpiry: parseDuration(getEnv("PASSWORD_RESET_EXPIRY", "1h"), time.Hour),
+			EmailVerification: strings.ToLower(getEnv("EMAIL_VERIFICATION", "readonly")),
+			EmailVerificationExpiry: parseDuration(getEnv("EMAIL_VERIFICATION_EXPIRY", "48h"), 48*time.Hour),
+			TwoFactorChallengeExpiry: parseDuration(getEnv("TWO_FACTOR_CHALLENGE_EXPIRY", "5m"), 5*time.Minute),
+			WorkspaceInvitationExpiry: parseDuration(getEnv("WORKSPACE_INVITATION_EXPIRY", "168h"), 168*time.Hour),
+			LoginMaxFailures: parseInt(getEnv("LOGIN_MAX_FAILURES", "5"), 5),
+			LoginIPMaxFailures: parseInt(getEnv("LOGIN_IP_MAX_FAILURES", "20"), 20),
+			LoginFailureWindow: parseDuration(getEnv("LOGIN_FAILURE_WINDOW", "15m"), 15*time.Minute),
+			LoginBackoffBase: parseDuration(getEnv("LOGIN_BACKOFF_BASE", "1s"), time.Second),
+			LoginBackoffMax: parseDuration(getEnv("LOGIN_BACKOFF_MAX", "1m"), time.Minute),
+			LoginLockoutDuration: parseDuration(getEnv("LOGIN_LOCKOUT_DURATION", "15m"), 15*time.Minute),
+		},
+		Mail: MailConfig{
+			Driver: getEnv("MAIL_DRIVER", "file"),
+			From: getEnv("MAIL_FROM", "YouDo <no-reply@youdo.local>"),
+			SMTPHost: getEnv("SMTP_HOST", "localhost"),
+			SMTPPort: getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			FileDir: getEnv("MAIL_FILE_DIR", "./tmp/mail"),
+		},
+		OIDC: OIDCConfig{
+			StateExpiry: parseDuration(getEnv("OIDC_STATE_EXPIRY", "10m"), 10*time.Minute),
+		},
+		Reminder: ReminderConfig{
+			SchedulerEnabled: getEnv("REMINDER_SCHEDULER", "true") == "true",
+			PollInterval: parseDuration(getEnv("REMINDER_POLL_INTERVAL", "30s"), 30*time.Second),
+			BatchSize: parseInt(getEnv("REMINDER_BATCH_SIZE", "100"), 100),
+			MaxAttempts: parseInt(getEnv("REMINDER_MAX_ATTEMPTS", "5"), 5),
+			RetryDelay: parseDuration(getEnv("REMINDER_RETRY_DELAY", "1m"), time.Minute),
+			WebhookSecret: getEnv("WEBHOOK_SIGNING_SECRET", ""),
+			WebhookTimeout: parseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"), 10*time.Second),
+		},
+		Trash: TrashConfig{
+			Retention: parseDuration(getEnv("TRASH_RETENTION", "720h"), 720*time.Hour),
+			PurgeInterval: parseDuration(getEnv("TRASH_PURGE_INTERVAL", "1h"), time.Hour),
 		},
 		Log: LogConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
 	}
 
+	config.OIDC.Providers = loadOIDCProviders(config.Server.PublicURL)
+
 	err := config.Validate()
 	
 	if err != nil {
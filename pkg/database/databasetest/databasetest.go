@@ -0,0 +1,106 @@
+// Package databasetest provides a migrated PostgreSQL schema for tests that
+// need a real database. The tests are skipped unless TEST_DATABASE_URL
+// points at a database the tests may create schemas in.
+package databasetest
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// Open creates an empty schema, applies every up migration to it and
+// returns a connection that uses it. The schema is dropped when the test
+// finishes.
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := "test_" + randomSuffix(t)
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("failed to drop schema %s: %v", schema, err)
+		}
+	})
+
+	db, err := sql.Open("postgres", withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("failed to open test schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrate(t, db)
+
+	return db
+}
+
+// withSearchPath adds search_path to the connection string; lib/pq sends
+// unknown parameters to the server as run-time settings.
+func withSearchPath(dsn, schema string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		// A key=value connection string.
+		return dsn + " search_path=" + schema
+	}
+
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func migrate(t testing.TB, db *sql.DB) {
+	t.Helper()
+
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("failed to locate the migrations")
+	}
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(file), "..", "..", "..", "migration", "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("failed to find migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	for _, path := range files {
+		migration, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if _, err := db.Exec(string(migration)); err != nil {
+			t.Fatalf("failed to apply %s: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func randomSuffix(t testing.TB) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("failed to generate schema name: %v", err)
+	}
+
+	return hex.EncodeToString(b)
+}
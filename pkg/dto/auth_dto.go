@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type RegisterRequest struct {
 	Name string `json:"name" binding:"required,min=2"`
 	Email string `json:"email" binding:"required,email"`
@@ -15,9 +17,87 @@ type UserResponse struct {
 	ID int `json:"id"`
 	Email string `json:"email"`
 	Name string `json:"name"`
+	EmailVerified bool `json:"email_verified"`
+	TwoFactorEnabled bool `json:"two_factor_enabled"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
 }
 
 type AuthResponse struct {
 	Token string `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RefreshToken string `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
 	User UserResponse `json:"user"`
-}
\ No newline at end of file
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool `json:"two_factor_required"`
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code string `json:"code" binding:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type IdentityResponse struct {
+	Provider string `json:"provider"`
+	Email string `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReauthResponse proves a fresh sign-in at a provider. Accounts without a
+// password pass the token to confirm sensitive changes.
+type ReauthResponse struct {
+	ReauthToken string `json:"reauth_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OIDCCallbackResponse carries the outcome of a provider callback: tokens or
+// a two-factor challenge after a login, the new identity after linking, or
+// a reauth token after signing in again.
+type OIDCCallbackResponse struct {
+	Auth *AuthResponse `json:"auth,omitempty"`
+	TwoFactor *TwoFactorChallengeResponse `json:"two_factor,omitempty"`
+	Identity *IdentityResponse `json:"identity,omitempty"`
+	Reauth *ReauthResponse `json:"reauth,omitempty"`
+}
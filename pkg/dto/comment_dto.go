@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// CreateCommentRequest mentions users by writing @ followed by their e-mail
+// address, e.g. @jane@example.com.
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=10000"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=10000"`
+}
+
+type CommentListQuery struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type CommentResponse struct {
+	ID     int    `json:"id"`
+	TaskID int    `json:"task_id"`
+	UserID int    `json:"user_id"`
+	Body   string `json:"body"`
+	// MentionIDs are the users mentioned in the body who can see the task.
+	MentionIDs []int     `json:"mention_ids"`
+	Edited     bool      `json:"edited"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type CommentListResponse struct {
+	Comments []CommentResponse `json:"comments"`
+	Total    int               `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
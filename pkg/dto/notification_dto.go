@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+type NotificationListQuery struct {
+	UnreadOnly bool `form:"unread_only"`
+	Limit      int  `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset     int  `form:"offset" binding:"omitempty,min=0"`
+}
+
+type NotificationResponse struct {
+	ID        int        `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	TaskID    *int       `json:"task_id,omitempty"`
+	IsRead    bool       `json:"is_read"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Total         int                    `json:"total"`
+	Unread        int                    `json:"unread"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+}
+
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+type MarkAllReadResponse struct {
+	Updated int `json:"updated"`
+}
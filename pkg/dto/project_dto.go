@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+type CreateProjectRequest struct {
+	Name  string `json:"name" binding:"required,min=1,max=100"`
+	Color string `json:"color" binding:"omitempty,hexcolor,len=7"`
+}
+
+type UpdateProjectRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Color    *string `json:"color" binding:"omitempty,hexcolor,len=7"`
+	Archived *bool   `json:"archived"`
+}
+
+type ProjectListQuery struct {
+	IncludeArchived bool `form:"include_archived"`
+}
+
+type DeleteProjectQuery struct {
+	Tasks string `form:"tasks" binding:"omitempty,oneof=move delete"`
+}
+
+type ProjectResponse struct {
+	ID             int        `json:"id"`
+	OwnerID        int        `json:"owner_id"`
+	WorkspaceID    int        `json:"workspace_id"`
+	Name           string     `json:"name"`
+	Color          string     `json:"color"`
+	Archived       bool       `json:"archived"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
+	OpenTasks      int        `json:"open_tasks"`
+	CompletedTasks int        `json:"completed_tasks"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// CreateReminderRequest needs either remind_at or offset_minutes.
+type CreateReminderRequest struct {
+	RemindAt      *string `json:"remind_at"`
+	OffsetMinutes *int    `json:"offset_minutes" binding:"omitempty,min=0,max=525600"`
+	Channel       string  `json:"channel" binding:"omitempty,oneof=email webhook in_app"`
+	WebhookURL    string  `json:"webhook_url" binding:"omitempty,url,max=2048"`
+}
+
+type ReminderResponse struct {
+	ID            int        `json:"id"`
+	TaskID        int        `json:"task_id"`
+	RemindAt      *time.Time `json:"remind_at,omitempty"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty"`
+	Channel       string     `json:"channel"`
+	WebhookURL    string     `json:"webhook_url,omitempty"`
+	FireAt        *time.Time `json:"fire_at,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	FailedAt      *time.Time `json:"failed_at,omitempty"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+type ShareRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=viewer editor"`
+}
+
+type ShareResponse struct {
+	UserID    int       `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
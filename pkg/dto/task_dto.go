@@ -7,29 +7,167 @@ type CreateTaskRequest struct {
 	Description string  `json:"description"`
 	Priority    string  `json:"priority" binding:"omitempty,oneof=low medium high"`
 	DueDate     *string `json:"due_date"`
+	ParentID    *int    `json:"parent_id" binding:"omitempty,min=1"`
+	TagIDs      []int   `json:"tag_ids" binding:"omitempty,dive,min=1"`
+	ProjectID   *int    `json:"project_id" binding:"omitempty,min=1"`
+	// StatusID is the board column of the task; it defaults to the first open status.
+	StatusID *int `json:"status_id" binding:"omitempty,min=1"`
+	// RecurrenceRule repeats the task, e.g. FREQ=WEEKLY;BYDAY=MO,TH. It needs a due date.
+	RecurrenceRule     string `json:"recurrence_rule" binding:"omitempty,max=255"`
+	RecurrenceTimezone string `json:"recurrence_timezone" binding:"omitempty,max=64"`
 }
 
 type UpdateTaskRequest struct {
-	Title       *string  `json:"title" binding:"required,min=1,max=255"`
-	Description *string  `json:"description"`
-	IsCompleted *bool   `json:"is_completed"`
-	Priority    *string  `json:"priority" binding:"omitempty,oneof=low medium high"`
-	DueDate     *string `json:"due_date"`
+	Title       *string `json:"title" binding:"required,min=1,max=255"`
+	Description *string `json:"description"`
+	// IsCompleted moves the task to the first done or open status unless status_id is given.
+	IsCompleted *bool `json:"is_completed"`
+	// StatusID moves the task to another board column and sets is_completed to match it.
+	StatusID *int    `json:"status_id" binding:"omitempty,min=1"`
+	Priority *string `json:"priority" binding:"omitempty,oneof=low medium high"`
+	DueDate  *string `json:"due_date"`
+	// ParentID moves the task under another task; 0 makes it a top-level task.
+	ParentID *int `json:"parent_id" binding:"omitempty,min=0"`
+	// TagIDs replaces the tags of the task when present; an empty list removes all tags.
+	TagIDs *[]int `json:"tag_ids"`
+	// ProjectID moves the task to another project; 0 moves it to the inbox.
+	ProjectID *int `json:"project_id" binding:"omitempty,min=0"`
+	// RecurrenceRule replaces the recurrence of the task; an empty string stops it.
+	RecurrenceRule     *string `json:"recurrence_rule" binding:"omitempty,max=255"`
+	RecurrenceTimezone *string `json:"recurrence_timezone" binding:"omitempty,max=64"`
+	// CompleteSubtasks also completes every subtask when is_completed is set to true.
+	CompleteSubtasks bool `json:"complete_subtasks"`
 }
 
 type TaskResponse struct {
-	ID          int    `json:"id"`
-	UserID      int    `json:"user_id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	IsCompleted bool   `json:"is_completed"`
-	Priority    string `json:"priority"`
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	WorkspaceID int        `json:"workspace_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	IsCompleted bool       `json:"is_completed"`
+	StatusID    int        `json:"status_id"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
 	DueDate     *time.Time `json:"due_date,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ParentID    *int       `json:"parent_id,omitempty"`
+	ProjectID   *int       `json:"project_id,omitempty"`
+	// AssigneeID is the user working on the task; UserID is its creator.
+	AssigneeID *int                     `json:"assignee_id,omitempty"`
+	Subtasks   *SubtaskProgressResponse `json:"subtasks,omitempty"`
+	Tags       []TagResponse            `json:"tags"`
+	// IsBlocked is set while any of the tasks in BlockedBy is not completed.
+	IsBlocked          bool   `json:"is_blocked"`
+	BlockedBy          []int  `json:"blocked_by"`
+	CommentCount       int    `json:"comment_count"`
+	Position           int64  `json:"position"`
+	RecurrenceRule     string `json:"recurrence_rule,omitempty"`
+	RecurrenceTimezone string `json:"recurrence_timezone,omitempty"`
+	// NextOccurrence is the task created when a recurring task is completed.
+	NextOccurrence *TaskResponse `json:"next_occurrence,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	DeletedAt      *time.Time    `json:"deleted_at,omitempty"`
 }
 
-type TaskListResponse struct {
-	Tasks []TaskResponse `json:"tasks"`
+// MoveTaskRequest places a task directly before or after another one in the
+// manual order. Exactly one of the two must be set.
+type MoveTaskRequest struct {
+	BeforeID *int `json:"before_id" binding:"omitempty,min=1"`
+	AfterID  *int `json:"after_id" binding:"omitempty,min=1"`
+}
+
+type AssignTaskRequest struct {
+	// UserID is the assignee; they need access to the task.
+	UserID int `json:"user_id" binding:"required,min=1"`
+}
+
+type AddBlockerRequest struct {
+	// BlockerID is the task that has to be completed first.
+	BlockerID int `json:"blocker_id" binding:"required,min=1"`
+}
+
+type SubtaskProgressResponse struct {
+	Done  int `json:"done"`
 	Total int `json:"total"`
-}
\ No newline at end of file
+}
+
+type TaskListQuery struct {
+	IsCompleted *bool `form:"is_completed"`
+	StatusID    int   `form:"status_id" binding:"omitempty,min=1"`
+	// Actionable keeps open tasks that are not blocked by another task.
+	Actionable bool   `form:"actionable"`
+	Priority   string `form:"priority" binding:"omitempty,oneof=low medium high"`
+	ProjectID  *int   `form:"project_id" binding:"omitempty,min=0"`
+	// Assignee keeps the tasks assigned to the user (me) or to nobody (unassigned).
+	Assignee    string     `form:"assignee" binding:"omitempty,oneof=me unassigned"`
+	DueFrom     *time.Time `form:"due_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueTo       *time.Time `form:"due_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedFrom *time.Time `form:"updated_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedTo   *time.Time `form:"updated_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Tags        []int      `form:"tags" binding:"omitempty,dive,min=1"`
+	TagMatch    string     `form:"tag_match" binding:"omitempty,oneof=any all"`
+	SortBy      string     `form:"sort_by" binding:"omitempty,oneof=created_at updated_at due_date priority title position"`
+	Order       string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit       int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset      int        `form:"offset" binding:"omitempty,min=0"`
+}
+
+type TaskListResponse struct {
+	Tasks  []TaskResponse `json:"tasks"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+type TrashListQuery struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type PurgeTrashResponse struct {
+	Purged int `json:"purged"`
+}
+
+type FieldChangeResponse struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type TaskEventResponse struct {
+	ID        int64                          `json:"id"`
+	TaskID    int                            `json:"task_id"`
+	ActorID   *int                           `json:"actor_id,omitempty"`
+	Action    string                         `json:"action"`
+	Changes   map[string]FieldChangeResponse `json:"changes"`
+	CreatedAt time.Time                      `json:"created_at"`
+}
+
+// BulkTaskRequest applies one action to many tasks. priority, due_date,
+// project_id and tag_id are the arguments of set_priority, set_due_date,
+// move_to_project and add_tag.
+type BulkTaskRequest struct {
+	TaskIDs  []int  `json:"task_ids" binding:"required,min=1,max=100,dive,min=1"`
+	Action   string `json:"action" binding:"required,oneof=complete uncomplete delete set_priority set_due_date move_to_project add_tag set_status"`
+	Priority string `json:"priority" binding:"omitempty,oneof=low medium high"`
+	// DueDate is the new due date; an empty string removes it.
+	DueDate *string `json:"due_date"`
+	// ProjectID is the target project; 0 moves the tasks to the inbox.
+	ProjectID *int `json:"project_id" binding:"omitempty,min=0"`
+	TagID     int  `json:"tag_id" binding:"omitempty,min=1"`
+	StatusID  int  `json:"status_id" binding:"omitempty,min=1"`
+}
+
+type BulkTaskItemResult struct {
+	TaskID  int    `json:"task_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BulkTaskResponse struct {
+	Action  string               `json:"action"`
+	Applied bool                 `json:"applied"`
+	Results []BulkTaskItemResult `json:"results"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+type CreateTaskStatusRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+	// Position places the status on the board; it defaults to the end.
+	Position *int `json:"position" binding:"omitempty,min=0"`
+	IsDone   bool `json:"is_done"`
+}
+
+type UpdateTaskStatusRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Position *int    `json:"position" binding:"omitempty,min=0"`
+	IsDone   *bool   `json:"is_done"`
+}
+
+type DeleteTaskStatusQuery struct {
+	// MoveTo is the status that receives the tasks of the deleted one.
+	MoveTo int `form:"move_to" binding:"omitempty,min=1"`
+}
+
+type TaskStatusResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Position  int       `json:"position"`
+	IsDone    bool      `json:"is_done"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type BoardQuery struct {
+	// ProjectID shows the board of one project; 0 shows the inbox.
+	ProjectID *int `form:"project_id" binding:"omitempty,min=0"`
+}
+
+type BoardColumnResponse struct {
+	Status TaskStatusResponse `json:"status"`
+	Tasks  []TaskResponse     `json:"tasks"`
+}
+
+type BoardResponse struct {
+	Columns []BoardColumnResponse `json:"columns"`
+	// Total is the number of tasks on the board. The columns hold at most
+	// 500 of them, the first ones in manual order.
+	Total int `json:"total"`
+}
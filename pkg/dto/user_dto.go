@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// UpdateProfileRequest changes the profile. Accounts with a password confirm
+// email and password changes with CurrentPassword; accounts that only sign
+// in through a provider confirm with a two-factor Code or a ReauthToken.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" binding:"omitempty,min=2"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	Password        *string `json:"password" binding:"omitempty,min=8"`
+	CurrentPassword string  `json:"current_password"`
+	Code            string  `json:"code"`
+	ReauthToken     string  `json:"reauth_token"`
+}
+
+// DeleteAccountRequest confirms an account deletion the same way as
+// UpdateProfileRequest confirms email and password changes.
+type DeleteAccountRequest struct {
+	Password    string `json:"password"`
+	Code        string `json:"code"`
+	ReauthToken string `json:"reauth_token"`
+}
+
+type CreatePersonalTokenRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write statuses:read statuses:write workspaces:read workspaces:write notifications:read notifications:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type PersonalTokenResponse struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreatedPersonalTokenResponse is only returned once, when the token is
+// created. Token cannot be retrieved again afterwards.
+type CreatedPersonalTokenResponse struct {
+	PersonalTokenResponse
+	Token string `json:"token"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+type UpdateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+type WorkspaceResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Role is the role of the authenticated user in the workspace.
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type UpdateWorkspaceMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+type WorkspaceMemberResponse struct {
+	UserID   int       `json:"user_id"`
+	Email    string    `json:"email"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	// Role is the role the invited user gets; it defaults to member.
+	Role string `json:"role" binding:"omitempty,oneof=admin member"`
+}
+
+type InvitationResponse struct {
+	ID        int       `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+)
+
+// errorStatus returns 403 when a service refused a change because of the
+// user's role and fallback for any other error.
+func errorStatus(err error, fallback int) int {
+	if errors.Is(err, service.ErrForbidden) {
+		return http.StatusForbidden
+	}
+	return fallback
+}
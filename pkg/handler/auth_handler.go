@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
 	"github.com/faisal-amiruddin/YouDo/pkg/service"
 	"github.com/faisal-amiruddin/YouDo/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -47,14 +52,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token. Accounts with two-factor authentication return a challenge token instead, to be completed at /api/auth/login/2fa. Failed attempts are throttled per account and per IP; throttled responses carry a Retry-After header.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "Login credentials"
 // @Success 200 {object} utils.Response{data=dto.AuthResponse}
+// @Success 202 {object} utils.Response{data=dto.TwoFactorChallengeResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 429 {object} utils.Response
 // @Router /api/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -64,11 +71,222 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(&req)
+	response, challenge, err := h.authService.Login(&req, c.ClientIP())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		loginErrorResponse(c, err)
+		return
+	}
+
+	if challenge != nil {
+		utils.SuccessResponse(c, http.StatusAccepted, "Two-factor authentication required", challenge)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Login successful", response)
-}
\ No newline at end of file
+}
+
+// LoginTwoFactor godoc
+// @Summary Complete two-factor login
+// @Description Exchange a login challenge token and a TOTP or recovery code for a JWT token. Wrong codes count as failed logins and are throttled the same way.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginTwoFactorRequest true "Challenge token and code"
+// @Success 200 {object} utils.Response{data=dto.AuthResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req dto.LoginTwoFactorRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.authService.LoginTwoFactor(&req, c.ClientIP())
+	if err != nil {
+		loginErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", response)
+}
+
+// loginErrorResponse reports a failed login step. Throttled attempts get a
+// Retry-After header and locked ones 429 Too Many Requests.
+func loginErrorResponse(c *gin.Context, err error) {
+	var throttled *service.LoginThrottleError
+	if errors.As(err, &throttled) {
+		if throttled.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		}
+		if throttled.Locked {
+			utils.ErrorResponse(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
+	}
+	utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+}
+
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated; reusing an old one revokes every token in its family.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} utils.Response{data=dto.AuthResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.authService.Refresh(&req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", response)
+}
+
+// Logout godoc
+// @Summary Logout user
+// @Description Revoke the refresh token family and, when a valid access token is sent in the Authorization header, the access token. The refresh token alone is enough, so an expired access token does not prevent logout.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param Authorization header string false "Bearer access token"
+// @Param request body dto.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	accessToken, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if accessToken == "" && req.RefreshToken == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "refresh token or access token required")
+		return
+	}
+
+	if err := h.authService.Logout(accessToken, &req); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logout successful", nil)
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Send a single-use password reset link to the given e-mail address. The response is the same whether or not the address is registered.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Account e-mail"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.authService.ForgotPassword(&req)
+
+	utils.SuccessResponse(c, http.StatusOK, "If the email is registered, a reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password using a reset token. All existing sessions are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.ResetPassword(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Confirm an email address using the token from the verification link. Access tokens issued before verification pick up the change on the next refresh.
+// @Tags auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/auth/verify [get]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Verification token required")
+		return
+	}
+
+	if err := h.authService.VerifyEmail(token); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verified successfully", nil)
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send a new verification link to the authenticated user's email address
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/auth/verify/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.authService.ResendVerification(userID); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Verification email sent", nil)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// CreateComment godoc
+// @Summary Comment on a task
+// @Description Add a comment to a task the user can see. Mention users with @ followed by their e-mail address (e.g. @jane@example.com); mentioned users who can see the task get a notification.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body dto.CreateCommentRequest true "Comment"
+// @Success 201 {object} utils.Response{data=dto.CommentResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/tasks/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(taskID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Comment created successfully", comment)
+}
+
+// GetComments godoc
+// @Summary Get the comments of a task
+// @Description Get a paginated list of the comments of a task, oldest first
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(50)
+// @Param offset query int false "Number of comments to skip" minimum(0) default(0)
+// @Success 200 {object} utils.Response{data=dto.CommentListResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/comments [get]
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var query dto.CommentListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comments, err := h.commentService.GetComments(taskID, userID, &query)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comments retrieved successfully", comments)
+}
+
+// UpdateComment godoc
+// @Summary Edit a comment
+// @Description Replace the body of one of your own comments. Users who are mentioned for the first time get a notification.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param commentId path int true "Comment ID"
+// @Param request body dto.UpdateCommentRequest true "New comment body"
+// @Success 200 {object} utils.Response{data=dto.CommentResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/comments/{commentId} [patch]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	commentID, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(taskID, commentID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment updated successfully", comment)
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Delete one of your own comments
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param commentId path int true "Comment ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	commentID, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	if err := h.commentService.DeleteComment(taskID, commentID, userID); err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment deleted successfully", nil)
+}
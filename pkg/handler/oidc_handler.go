@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCHandler struct {
+	oidcService *service.OIDCService
+}
+
+func NewOIDCHandler(oidcService *service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+// Login godoc
+// @Summary Start social login
+// @Description Return the URL of the provider's sign-in page. The authorization code flow uses PKCE.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} utils.Response{data=dto.OIDCAuthorizationResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	response, err := h.oidcService.BeginLogin(c.Param("provider"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Authorization URL created", response)
+}
+
+// Callback godoc
+// @Summary Complete social login
+// @Description Exchange the authorization code returned by the provider. Logins return tokens, or a two-factor challenge for accounts with two-factor authentication; link requests return the linked identity and reauthentication requests a reauth token.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State returned by the provider"
+// @Success 200 {object} utils.Response{data=dto.OIDCCallbackResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if errorCode := c.Query("error"); errorCode != "" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Sign-in was cancelled: "+errorCode)
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Missing code or state")
+		return
+	}
+
+	response, err := h.oidcService.Callback(c.Param("provider"), code, state)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sign-in completed", response)
+}
+
+// ListIdentities godoc
+// @Summary List linked providers
+// @Description Get the external sign-in providers linked to the current user
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.IdentityResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/users/me/identities [get]
+func (h *OIDCHandler) ListIdentities(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	identities, err := h.oidcService.ListIdentities(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Identities retrieved successfully", identities)
+}
+
+// LinkIdentity godoc
+// @Summary Link a provider
+// @Description Start linking an external sign-in provider to the current user. Returns the provider's sign-in URL; the link is created by the callback.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "Provider name"
+// @Success 200 {object} utils.Response{data=dto.OIDCAuthorizationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/users/me/identities/{provider} [post]
+func (h *OIDCHandler) LinkIdentity(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	response, err := h.oidcService.BeginLink(userID, c.Param("provider"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Authorization URL created", response)
+}
+
+// Reauthenticate godoc
+// @Summary Sign in again with a provider
+// @Description Start a fresh sign-in with a provider linked to the current user. The callback returns a short-lived reauth token, which accounts without a password pass to change their email or password or to delete the account.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "Provider name"
+// @Success 200 {object} utils.Response{data=dto.OIDCAuthorizationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/users/me/identities/{provider}/reauth [post]
+func (h *OIDCHandler) Reauthenticate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	response, err := h.oidcService.BeginReauth(userID, c.Param("provider"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Authorization URL created", response)
+}
+
+// UnlinkIdentity godoc
+// @Summary Unlink a provider
+// @Description Remove a linked sign-in provider. The last provider of an account without a password cannot be removed; set a password or link another provider first.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "Provider name"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/users/me/identities/{provider} [delete]
+func (h *OIDCHandler) UnlinkIdentity(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.oidcService.Unlink(userID, c.Param("provider")); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Identity unlinked successfully", nil)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalTokenHandler struct {
+	personalTokenService *service.PersonalTokenService
+}
+
+func NewPersonalTokenHandler(personalTokenService *service.PersonalTokenService) *PersonalTokenHandler {
+	return &PersonalTokenHandler{personalTokenService: personalTokenService}
+}
+
+// CreateToken godoc
+// @Summary Create a personal access token
+// @Description Create a named API token with the given scopes: tasks, statuses, workspaces and notifications, each with :read or :write. The token value is only returned in this response.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreatePersonalTokenRequest true "Token details"
+// @Success 201 {object} utils.Response{data=dto.CreatedPersonalTokenResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/users/me/tokens [post]
+func (h *PersonalTokenHandler) CreateToken(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req dto.CreatePersonalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, err := h.personalTokenService.CreateToken(userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Token created successfully", token)
+}
+
+// GetTokens godoc
+// @Summary List personal access tokens
+// @Description List the personal access tokens of the authenticated user
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.PersonalTokenResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/users/me/tokens [get]
+func (h *PersonalTokenHandler) GetTokens(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	tokens, err := h.personalTokenService.ListTokens(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tokens retrieved successfully", tokens)
+}
+
+// DeleteToken godoc
+// @Summary Revoke a personal access token
+// @Description Delete a personal access token so it can no longer be used
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Token ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/me/tokens/{id} [delete]
+func (h *PersonalTokenHandler) DeleteToken(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	tokenID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	if err := h.personalTokenService.RevokeToken(tokenID, userID); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token revoked successfully", nil)
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectHandler struct {
+	projectService *service.ProjectService
+}
+
+func NewProjectHandler(projectService *service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{projectService: projectService}
+}
+
+// CreateProject godoc
+// @Summary Create a project
+// @Description Create a new project in the workspace of the request
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateProjectRequest true "Project details"
+// @Param X-Workspace-ID header int false "Workspace to work in; defaults to the first workspace of the user"
+// @Success 201 {object} utils.Response{data=dto.ProjectResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, exists := middleware.GetWorkspaceID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req dto.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	project, err := h.projectService.CreateProject(userID, workspaceID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Project created successfully", project)
+}
+
+// GetProjects godoc
+// @Summary Get all projects
+// @Description Get the projects in the workspace of the request and the projects shared with the authenticated user, with their open and completed task counts
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param include_archived query bool false "Include archived projects" default(false)
+// @Param X-Workspace-ID header int false "Workspace to work in; defaults to the first workspace of the user"
+// @Success 200 {object} utils.Response{data=[]dto.ProjectResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/projects [get]
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, exists := middleware.GetWorkspaceID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var query dto.ProjectListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	projects, err := h.projectService.GetProjects(userID, workspaceID, &query)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Projects retrieved successfully", projects)
+}
+
+// GetProject godoc
+// @Summary Get a project by ID
+// @Description Get a single project with its open and completed task counts
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} utils.Response{data=dto.ProjectResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/projects/{id} [get]
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid project ID")
+		return
+	}
+
+	project, err := h.projectService.GetProject(projectID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Project retrieved successfully", project)
+}
+
+// UpdateProject godoc
+// @Summary Update a project
+// @Description Rename a project, change its color, or archive or unarchive it. Archived projects do not accept new tasks.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param request body dto.UpdateProjectRequest true "Updated project details"
+// @Success 200 {object} utils.Response{data=dto.ProjectResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/projects/{id} [patch]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid project ID")
+		return
+	}
+
+	var req dto.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	project, err := h.projectService.UpdateProject(projectID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Project updated successfully", project)
+}
+
+// DeleteProject godoc
+// @Summary Delete a project
+// @Description Delete a project. Its tasks are moved to the inbox, or deleted with tasks=delete.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param tasks query string false "What to do with the project's tasks" Enums(move, delete) default(move)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid project ID")
+		return
+	}
+
+	var query dto.DeleteProjectQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.projectService.DeleteProject(projectID, userID, &query); err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Project deleted successfully", nil)
+}
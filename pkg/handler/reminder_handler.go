@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ReminderHandler struct {
+	reminderService *service.ReminderService
+}
+
+func NewReminderHandler(reminderService *service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{reminderService: reminderService}
+}
+
+// CreateReminder godoc
+// @Summary Add a reminder to a task
+// @Description Remind the user at remind_at or offset_minutes before the due date of a task they can see. Offset reminders follow changes to the due date. Delivered by e-mail, webhook or as an in-app notification (default). Webhook URLs must resolve to public addresses.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body dto.CreateReminderRequest true "Reminder details"
+// @Success 201 {object} utils.Response{data=dto.ReminderResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/tasks/{id}/reminders [post]
+func (h *ReminderHandler) CreateReminder(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req dto.CreateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reminder, err := h.reminderService.CreateReminder(taskID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Reminder created successfully", reminder)
+}
+
+// GetReminders godoc
+// @Summary Get the reminders of a task
+// @Description Get the user's own reminders of a task, including sent and failed ones
+// @Tags reminders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} utils.Response{data=[]dto.ReminderResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/reminders [get]
+func (h *ReminderHandler) GetReminders(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	reminders, err := h.reminderService.GetReminders(taskID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reminders retrieved successfully", reminders)
+}
+
+// DeleteReminder godoc
+// @Summary Delete a reminder
+// @Description Delete one of the user's own reminders of a task
+// @Tags reminders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param reminderId path int true "Reminder ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/reminders/{reminderId} [delete]
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	reminderID, err := strconv.Atoi(c.Param("reminderId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reminder ID")
+		return
+	}
+
+	if err := h.reminderService.DeleteReminder(reminderID, taskID, userID); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reminder deleted successfully", nil)
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ShareHandler struct {
+	accessService *service.AccessService
+}
+
+func NewShareHandler(accessService *service.AccessService) *ShareHandler {
+	return &ShareHandler{accessService: accessService}
+}
+
+// ShareTask godoc
+// @Summary Share a task
+// @Description Give another registered user viewer or editor access to a task and its subtasks. Sharing again with the same user changes their role. Only the owner and the admins of its workspace can share.
+// @Tags shares
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body dto.ShareRequest true "Recipient and role"
+// @Success 200 {object} utils.Response{data=dto.ShareResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/tasks/{id}/shares [post]
+func (h *ShareHandler) ShareTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req dto.ShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	share, err := h.accessService.ShareTask(taskID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task shared successfully", share)
+}
+
+// GetTaskShares godoc
+// @Summary Get the shares of a task
+// @Description List the users a task is shared with directly
+// @Tags shares
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} utils.Response{data=[]dto.ShareResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/shares [get]
+func (h *ShareHandler) GetTaskShares(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	shares, err := h.accessService.GetTaskShares(taskID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shares retrieved successfully", shares)
+}
+
+// UnshareTask godoc
+// @Summary Revoke a task share
+// @Description Revoke the access of a user to a task. Whoever can share it can revoke any share and recipients can remove their own. Takes effect immediately.
+// @Tags shares
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param userId path int true "User ID of the recipient"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/shares/{userId} [delete]
+func (h *ShareHandler) UnshareTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	recipientID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.accessService.UnshareTask(taskID, userID, recipientID); err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Share revoked successfully", nil)
+}
+
+// ShareProject godoc
+// @Summary Share a project
+// @Description Give another registered user viewer or editor access to a project and all of its tasks. Sharing again with the same user changes their role. Only the owner and the admins of its workspace can share.
+// @Tags shares
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param request body dto.ShareRequest true "Recipient and role"
+// @Success 200 {object} utils.Response{data=dto.ShareResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/projects/{id}/shares [post]
+func (h *ShareHandler) ShareProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid project ID")
+		return
+	}
+
+	var req dto.ShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	share, err := h.accessService.ShareProject(projectID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Project shared successfully", share)
+}
+
+// GetProjectShares godoc
+// @Summary Get the shares of a project
+// @Description List the users a project is shared with
+// @Tags shares
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} utils.Response{data=[]dto.ShareResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/projects/{id}/shares [get]
+func (h *ShareHandler) GetProjectShares(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid project ID")
+		return
+	}
+
+	shares, err := h.accessService.GetProjectShares(projectID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shares retrieved successfully", shares)
+}
+
+// UnshareProject godoc
+// @Summary Revoke a project share
+// @Description Revoke the access of a user to a project. Whoever can share it can revoke any share and recipients can remove their own. Takes effect immediately.
+// @Tags shares
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param userId path int true "User ID of the recipient"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/projects/{id}/shares/{userId} [delete]
+func (h *ShareHandler) UnshareProject(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid project ID")
+		return
+	}
+
+	recipientID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.accessService.UnshareProject(projectID, userID, recipientID); err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Share revoked successfully", nil)
+}
@@ -21,16 +21,16 @@ func NewTaskHandler(taskService *service.TaskService) *TaskHandler {
 
 // CreateTask godoc
 // @Summary Create a new task
-// @Description Create a new task for the authenticated user
+// @Description Create a new task in the workspace of the request. Set parent_id to create it as a subtask; subtasks and tasks of a project go to the workspace of their parent or project. recurrence_rule takes an RRULE with FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY, BYMONTHDAY (monthly rules only), COUNT and UNTIL, evaluated in recurrence_timezone (default UTC).
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body dto.CreateTaskRequest true "Task details"
+// @Param X-Workspace-ID header int false "Workspace to work in; defaults to the first workspace of the user"
 // @Success 201 {object} utils.Response{data=dto.TaskResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
-// @Failure 500 {object} utils.Response
 // @Router /api/tasks [post]
 func (h *TaskHandler) CreateTask(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
@@ -39,15 +39,21 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
+	workspaceID, exists := middleware.GetWorkspaceID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	var req dto.CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	task, err := h.taskService.CreateTask(userID, &req)
+	task, err := h.taskService.CreateTask(userID, workspaceID, &req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
 		return
 	}
 
@@ -56,11 +62,31 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 
 // GetAllTasks godoc
 // @Summary Get all tasks
-// @Description Get all tasks for the authenticated user
+// @Description Get a filtered, sorted and paginated list of the tasks in the workspace of the request and the tasks shared with the authenticated user. Date ranges use RFC 3339 timestamps.
 // @Tags tasks
 // @Produce json
 // @Security BearerAuth
+// @Param is_completed query bool false "Filter by completion state"
+// @Param status_id query int false "Filter by status"
+// @Param actionable query bool false "Only open tasks whose blockers are all completed"
+// @Param priority query string false "Filter by priority" Enums(low, medium, high)
+// @Param project_id query int false "Filter by project; 0 selects tasks without a project"
+// @Param assignee query string false "Only tasks assigned to the authenticated user or to nobody" Enums(me, unassigned)
+// @Param due_from query string false "Due date lower bound (RFC 3339)"
+// @Param due_to query string false "Due date upper bound (RFC 3339)"
+// @Param created_from query string false "Created at lower bound (RFC 3339)"
+// @Param created_to query string false "Created at upper bound (RFC 3339)"
+// @Param updated_from query string false "Updated at lower bound (RFC 3339)"
+// @Param updated_to query string false "Updated at upper bound (RFC 3339)"
+// @Param tags query []int false "Filter by tag IDs" collectionFormat(multi)
+// @Param tag_match query string false "Match any or all of the tags" Enums(any, all) default(any)
+// @Param sort_by query string false "Sort field" Enums(created_at, updated_at, due_date, priority, title, position) default(created_at)
+// @Param order query string false "Sort direction, ascending by default for position" Enums(asc, desc) default(desc)
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(50)
+// @Param offset query int false "Number of tasks to skip" minimum(0) default(0)
+// @Param X-Workspace-ID header int false "Workspace to work in; defaults to the first workspace of the user"
 // @Success 200 {object} utils.Response{data=dto.TaskListResponse}
+// @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /api/tasks [get]
@@ -71,7 +97,19 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 		return
 	}
 
-	tasks, err := h.taskService.GetAllTasks(userID)
+	workspaceID, exists := middleware.GetWorkspaceID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var query dto.TaskListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tasks, err := h.taskService.GetAllTasks(userID, workspaceID, &query)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -107,7 +145,7 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 
 	task, err := h.taskService.GetTask(taskID, userID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
 		return
 	}
 
@@ -116,7 +154,7 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 
 // UpdateTask godoc
 // @Summary Update a task
-// @Description Update an existing task
+// @Description Update an existing task. Set complete_subtasks together with is_completed to complete all of its subtasks as well. Completing a recurring task creates its next occurrence, returned as next_occurrence.
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -149,16 +187,218 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 
 	task, err := h.taskService.UpdateTask(taskID, userID, &req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Task updated successfully", task)
 }
 
+// AddBlocker godoc
+// @Summary Add a blocker
+// @Description Make a task depend on another task in the same workspace that has to be completed first. The user needs edit access to the task and view access to the blocker. Dependencies that would create a cycle are rejected.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body dto.AddBlockerRequest true "Blocking task"
+// @Success 200 {object} utils.Response{data=dto.TaskResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/tasks/{id}/blockers [post]
+func (h *TaskHandler) AddBlocker(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req dto.AddBlockerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := h.taskService.AddBlocker(taskID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Blocker added successfully", task)
+}
+
+// RemoveBlocker godoc
+// @Summary Remove a blocker
+// @Description Remove the dependency of a task on another task
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param blockerId path int true "Blocking task ID"
+// @Success 200 {object} utils.Response{data=dto.TaskResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/blockers/{blockerId} [delete]
+func (h *TaskHandler) RemoveBlocker(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	blockerID, err := strconv.Atoi(c.Param("blockerId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid blocker ID")
+		return
+	}
+
+	task, err := h.taskService.RemoveBlocker(taskID, blockerID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Blocker removed successfully", task)
+}
+
+// AssignTask godoc
+// @Summary Assign a task
+// @Description Make a user responsible for a task and notify them. The assignee needs access to the task through its workspace or a share; user_id of the task stays its creator.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body dto.AssignTaskRequest true "Assignee"
+// @Success 200 {object} utils.Response{data=dto.TaskResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/tasks/{id}/assignee [put]
+func (h *TaskHandler) AssignTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req dto.AssignTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := h.taskService.AssignTask(taskID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task assigned successfully", task)
+}
+
+// UnassignTask godoc
+// @Summary Unassign a task
+// @Description Remove the assignee of a task
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} utils.Response{data=dto.TaskResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/assignee [delete]
+func (h *TaskHandler) UnassignTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	task, err := h.taskService.UnassignTask(taskID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task unassigned successfully", task)
+}
+
+// MoveTask godoc
+// @Summary Reorder a task
+// @Description Move a task directly before or after another task of the same workspace in the manual order of the workspace, used when tasks are listed with sort_by=position.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body dto.MoveTaskRequest true "Task to place it before or after"
+// @Success 200 {object} utils.Response{data=dto.TaskResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/tasks/{id}/reorder [post]
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req dto.MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := h.taskService.MoveTask(taskID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task moved successfully", task)
+}
+
 // DeleteTask godoc
 // @Summary Delete a task
-// @Description Delete a task by its ID
+// @Description Move a task and all of its subtasks to the trash. Trashed tasks can be restored until they are purged.
 // @Tags tasks
 // @Produce json
 // @Security BearerAuth
@@ -182,9 +422,339 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	}
 
 	if err := h.taskService.DeleteTask(taskID, userID); err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Task deleted successfully", nil)
-}
\ No newline at end of file
+}
+
+// CreateSubtask godoc
+// @Summary Create a subtask
+// @Description Create a task under an existing task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Parent task ID"
+// @Param request body dto.CreateTaskRequest true "Subtask details"
+// @Success 201 {object} utils.Response{data=dto.TaskResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/tasks/{id}/subtasks [post]
+func (h *TaskHandler) CreateSubtask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req dto.CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := h.taskService.CreateSubtask(taskID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Subtask created successfully", task)
+}
+
+// GetSubtasks godoc
+// @Summary Get subtasks
+// @Description Get the direct subtasks of a task
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Parent task ID"
+// @Success 200 {object} utils.Response{data=[]dto.TaskResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/subtasks [get]
+func (h *TaskHandler) GetSubtasks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	tasks, err := h.taskService.GetSubtasks(taskID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Subtasks retrieved successfully", tasks)
+}
+
+// GetBoard godoc
+// @Summary Get the board
+// @Description Get the top-level tasks in the workspace of the request grouped by status, one column per status of the authenticated user in board order with tasks in manual order. Tasks of other members appear in the column with the same name, or else the first one of the same kind. At most 500 tasks are included.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param project_id query int false "Show the board of a project; 0 shows the inbox"
+// @Param X-Workspace-ID header int false "Workspace to work in; defaults to the first workspace of the user"
+// @Success 200 {object} utils.Response{data=dto.BoardResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/tasks/board [get]
+func (h *TaskHandler) GetBoard(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, exists := middleware.GetWorkspaceID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var query dto.BoardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	board, err := h.taskService.GetBoard(userID, workspaceID, &query)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Board retrieved successfully", board)
+}
+
+// GetTrash godoc
+// @Summary Get the trash
+// @Description Get the deleted tasks in the workspace of the request, most recently deleted first. Subtasks deleted together with their parent are restored with it and are not listed separately.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(50)
+// @Param offset query int false "Number of tasks to skip" minimum(0) default(0)
+// @Success 200 {object} utils.Response{data=dto.TaskListResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/tasks/trash [get]
+func (h *TaskHandler) GetTrash(c *gin.Context) {
+	workspaceID, exists := middleware.GetWorkspaceID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var query dto.TrashListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tasks, err := h.taskService.GetTrash(workspaceID, &query)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trash retrieved successfully", tasks)
+}
+
+// EmptyTrash godoc
+// @Summary Empty the trash
+// @Description Permanently delete the tasks in the trash of the workspace of the request. Admins and the owner of the workspace empty the whole trash; other members only delete their own tasks.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.PurgeTrashResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/tasks/trash [delete]
+func (h *TaskHandler) EmptyTrash(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, exists := middleware.GetWorkspaceID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	role, exists := middleware.GetWorkspaceRole(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	result, err := h.taskService.EmptyTrash(userID, workspaceID, role)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trash emptied successfully", result)
+}
+
+// RestoreTask godoc
+// @Summary Restore a task
+// @Description Restore a task from the trash together with the subtasks deleted with it. Needs the editor role on the task. If its parent is still in the trash it becomes a top-level task.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} utils.Response{data=dto.TaskResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/restore [post]
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	task, err := h.taskService.RestoreTask(taskID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task restored successfully", task)
+}
+
+// PurgeTask godoc
+// @Summary Permanently delete a task
+// @Description Permanently delete a task from the trash, together with its subtasks. Only its owner and the admins of its workspace can do this. This cannot be undone.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/purge [delete]
+func (h *TaskHandler) PurgeTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	if err := h.taskService.PurgeTask(taskID, userID); err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task permanently deleted", nil)
+}
+
+// GetTaskHistory godoc
+// @Summary Get the history of a task
+// @Description Get the change history of a task, newest first. Each event records who made the change, the action (created, updated, completed, deleted or restored) and the old and new value of every changed field.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} utils.Response{data=[]dto.TaskEventResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tasks/{id}/history [get]
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	events, err := h.taskService.GetTaskHistory(taskID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task history retrieved successfully", events)
+}
+
+// BulkUpdateTasks godoc
+// @Summary Apply an action to many tasks
+// @Description Apply one action (complete, uncomplete, delete, set_priority, set_due_date, move_to_project, add_tag or set_status) to up to 100 tasks of one workspace in a single transaction, including tasks of other members. Tasks of another member get their own status or tag with the same name as the one given and fail when their owner has none. Either all tasks are changed or none: when a task is missing or cannot be changed, the response lists the error for that task and nothing is applied.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BulkTaskRequest true "Task IDs and action"
+// @Success 200 {object} utils.Response{data=dto.BulkTaskResponse}
+// @Failure 400 {object} utils.Response{data=dto.BulkTaskResponse}
+// @Failure 401 {object} utils.Response
+// @Router /api/tasks/bulk [post]
+func (h *TaskHandler) BulkUpdateTasks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req dto.BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.taskService.BulkUpdateTasks(userID, &req)
+	if err != nil {
+		if result != nil {
+			utils.ErrorDataResponse(c, errorStatus(err, http.StatusBadRequest), err.Error(), result)
+			return
+		}
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tasks updated successfully", result)
+}
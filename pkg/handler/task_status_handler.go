@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TaskStatusHandler struct {
+	statusService *service.TaskStatusService
+}
+
+func NewTaskStatusHandler(statusService *service.TaskStatusService) *TaskStatusHandler {
+	return &TaskStatusHandler{statusService: statusService}
+}
+
+// CreateStatus godoc
+// @Summary Create a status
+// @Description Add a column to the board of the authenticated user. Tasks in a status with is_done count as completed.
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateTaskStatusRequest true "Status details"
+// @Success 201 {object} utils.Response{data=dto.TaskStatusResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/statuses [post]
+func (h *TaskStatusHandler) CreateStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req dto.CreateTaskStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := h.statusService.CreateStatus(userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Status created successfully", status)
+}
+
+// GetStatuses godoc
+// @Summary Get all statuses
+// @Description Get the board columns of the authenticated user in board order. New users start with To Do, In Progress, Blocked, In Review and Done.
+// @Tags statuses
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.TaskStatusResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/statuses [get]
+func (h *TaskStatusHandler) GetStatuses(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	statuses, err := h.statusService.GetStatuses(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Statuses retrieved successfully", statuses)
+}
+
+// UpdateStatus godoc
+// @Summary Update a status
+// @Description Rename or reorder a status, or change whether it counts as done. Changing is_done completes or reopens the tasks in it. At least one open and one done status must remain.
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Status ID"
+// @Param request body dto.UpdateTaskStatusRequest true "Updated status details"
+// @Success 200 {object} utils.Response{data=dto.TaskStatusResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/statuses/{id} [patch]
+func (h *TaskStatusHandler) UpdateStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	statusID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid status ID")
+		return
+	}
+
+	var req dto.UpdateTaskStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := h.statusService.UpdateStatus(statusID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Status updated successfully", status)
+}
+
+// DeleteStatus godoc
+// @Summary Delete a status
+// @Description Delete a status. A status that still has tasks can only be deleted with move_to, the status its tasks are moved to.
+// @Tags statuses
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Status ID"
+// @Param move_to query int false "Status that receives the tasks"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/statuses/{id} [delete]
+func (h *TaskStatusHandler) DeleteStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	statusID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid status ID")
+		return
+	}
+
+	var query dto.DeleteTaskStatusQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.statusService.DeleteStatus(statusID, userID, &query); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Status deleted successfully", nil)
+}
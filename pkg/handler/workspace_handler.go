@@ -0,0 +1,467 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/middleware"
+	"github.com/faisal-amiruddin/YouDo/pkg/service"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type WorkspaceHandler struct {
+	workspaceService *service.WorkspaceService
+}
+
+func NewWorkspaceHandler(workspaceService *service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
+
+// CreateWorkspace godoc
+// @Summary Create a workspace
+// @Description Create a workspace owned by the authenticated user. Select it for other requests with the X-Workspace-ID header.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateWorkspaceRequest true "Workspace details"
+// @Success 201 {object} utils.Response{data=dto.WorkspaceResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req dto.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	workspace, err := h.workspaceService.CreateWorkspace(userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Workspace created successfully", workspace)
+}
+
+// GetWorkspaces godoc
+// @Summary Get all workspaces
+// @Description Get the workspaces the authenticated user is a member of with their role in each, starting with the default workspace
+// @Tags workspaces
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.WorkspaceResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/workspaces [get]
+func (h *WorkspaceHandler) GetWorkspaces(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaces, err := h.workspaceService.GetWorkspaces(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workspaces retrieved successfully", workspaces)
+}
+
+// GetWorkspace godoc
+// @Summary Get a workspace by ID
+// @Description Get a workspace the authenticated user is a member of
+// @Tags workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Success 200 {object} utils.Response{data=dto.WorkspaceResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/workspaces/{id} [get]
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	workspace, err := h.workspaceService.GetWorkspace(workspaceID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workspace retrieved successfully", workspace)
+}
+
+// UpdateWorkspace godoc
+// @Summary Rename a workspace
+// @Description Rename a workspace. Only admins and the owner can rename it.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param request body dto.UpdateWorkspaceRequest true "Updated workspace details"
+// @Success 200 {object} utils.Response{data=dto.WorkspaceResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/workspaces/{id} [patch]
+func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	var req dto.UpdateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	workspace, err := h.workspaceService.UpdateWorkspace(workspaceID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workspace updated successfully", workspace)
+}
+
+// DeleteWorkspace godoc
+// @Summary Delete a workspace
+// @Description Delete a workspace together with all of its tasks and projects. Only the owner can delete it.
+// @Tags workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/workspaces/{id} [delete]
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	if err := h.workspaceService.DeleteWorkspace(workspaceID, userID); err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workspace deleted successfully", nil)
+}
+
+// GetMembers godoc
+// @Summary Get the members of a workspace
+// @Description List the members of a workspace with their roles
+// @Tags workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Success 200 {object} utils.Response{data=[]dto.WorkspaceMemberResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/workspaces/{id}/members [get]
+func (h *WorkspaceHandler) GetMembers(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	members, err := h.workspaceService.GetMembers(workspaceID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Members retrieved successfully", members)
+}
+
+// UpdateMember godoc
+// @Summary Change the role of a member
+// @Description Make a member an admin or a regular member. Admins can change regular members; only the owner can appoint or change admins. The owner's role cannot be changed.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param userId path int true "User ID of the member"
+// @Param request body dto.UpdateWorkspaceMemberRequest true "New role"
+// @Success 200 {object} utils.Response{data=dto.WorkspaceMemberResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/workspaces/{id}/members/{userId} [patch]
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	memberID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req dto.UpdateWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, err := h.workspaceService.UpdateMember(workspaceID, userID, memberID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Member updated successfully", member)
+}
+
+// RemoveMember godoc
+// @Summary Remove a member
+// @Description Remove a member from a workspace, or leave it by passing your own user ID. Admins can remove regular members; only the owner can remove admins. The owner cannot leave. Takes effect immediately.
+// @Tags workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param userId path int true "User ID of the member"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/workspaces/{id}/members/{userId} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	memberID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(workspaceID, userID, memberID); err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Member removed successfully", nil)
+}
+
+// InviteMember godoc
+// @Summary Invite a member
+// @Description E-mail an invitation to join a workspace. The link expires and can only be accepted by an account with the invited address. Inviting the same address again replaces the earlier invitation. Only the owner can invite admins.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param request body dto.CreateInvitationRequest true "Invitation details"
+// @Success 201 {object} utils.Response{data=dto.InvitationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/workspaces/{id}/invitations [post]
+func (h *WorkspaceHandler) InviteMember(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	var req dto.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invitation, err := h.workspaceService.InviteMember(workspaceID, userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Invitation sent successfully", invitation)
+}
+
+// GetInvitations godoc
+// @Summary Get the pending invitations of a workspace
+// @Description List the invitations that were neither accepted nor expired
+// @Tags workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Success 200 {object} utils.Response{data=[]dto.InvitationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/workspaces/{id}/invitations [get]
+func (h *WorkspaceHandler) GetInvitations(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	invitations, err := h.workspaceService.GetInvitations(workspaceID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+// DeleteInvitation godoc
+// @Summary Withdraw an invitation
+// @Description Withdraw a pending invitation so its link can no longer be used
+// @Tags workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param invitationId path int true "Invitation ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/workspaces/{id}/invitations/{invitationId} [delete]
+func (h *WorkspaceHandler) DeleteInvitation(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	invitationID, err := strconv.Atoi(c.Param("invitationId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invitation ID")
+		return
+	}
+
+	if err := h.workspaceService.DeleteInvitation(workspaceID, invitationID, userID); err != nil {
+		utils.ErrorResponse(c, errorStatus(err, http.StatusNotFound), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation withdrawn successfully", nil)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept an invitation
+// @Description Join the workspace of an invitation sent to the email address of the authenticated user
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AcceptInvitationRequest true "Invitation token from the e-mail"
+// @Success 200 {object} utils.Response{data=dto.WorkspaceResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/workspaces/invitations/accept [post]
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req dto.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	workspace, err := h.workspaceService.AcceptInvitation(userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation accepted successfully", workspace)
+}
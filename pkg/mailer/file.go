@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer writes every message into a maildir style directory instead of
+// sending it. It is meant for development and tests.
+type FileMailer struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+func (m *FileMailer) Send(msg *Message) error {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(m.dir, sub), 0o755); err != nil {
+			return fmt.Errorf("failed to create mail directory: %w", err)
+		}
+	}
+
+	name := fmt.Sprintf("%d.%d.%d.eml", time.Now().UnixNano(), os.Getpid(), m.seq.Add(1))
+	tmpPath := filepath.Join(m.dir, "tmp", name)
+
+	if err := os.WriteFile(tmpPath, buildMessage(m.from, msg), 0o644); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(m.dir, "new", name)); err != nil {
+		return fmt.Errorf("failed to deliver mail: %w", err)
+	}
+
+	return nil
+}
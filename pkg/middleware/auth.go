@@ -2,13 +2,42 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
 	"github.com/faisal-amiruddin/YouDo/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// TokenDenylist reports whether an access token has been revoked before
+// its expiry, e.g. on logout.
+type TokenDenylist interface {
+	IsAccessTokenDenied(jti string) (bool, error)
+}
+
+// PersonalTokenAuthenticator resolves a personal access token to its record
+// and owner.
+type PersonalTokenAuthenticator interface {
+	AuthenticatePersonalToken(token string) (*model.PersonalAccessToken, *model.User, error)
+}
+
+// WorkspaceHeader selects the workspace a request works in. Without it the
+// user's default workspace is used.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// WorkspaceResolver returns the membership of a user in the workspace a
+// request works in. A zero workspaceID selects the user's default
+// workspace.
+type WorkspaceResolver interface {
+	ResolveWorkspace(userID, workspaceID int) (*model.WorkspaceMember, error)
+}
+
+// AuthMiddleware accepts either a JWT access token or, when personalTokens
+// is set, a personal access token in the Authorization header. When
+// workspaces is set it also puts the workspace of the request and the
+// user's role in it into the context.
+func AuthMiddleware(jwtSecret string, denylist TokenDenylist, personalTokens PersonalTokenAuthenticator, workspaces WorkspaceResolver) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -32,6 +61,30 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
+		if personalTokens != nil && strings.HasPrefix(tokenString, model.PersonalTokenPrefix) {
+			token, user, err := personalTokens.AuthenticatePersonalToken(tokenString)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"error":   "Invalid or expired token",
+				})
+				c.Abort()
+				return
+			}
+
+			c.Set("userID", user.ID)
+			c.Set("userEmail", user.Email)
+			c.Set("emailVerified", user.EmailVerifiedAt.Valid)
+			c.Set("tokenScopes", token.Scopes)
+
+			if !setWorkspace(c, workspaces, user.ID) {
+				return
+			}
+
+			c.Next()
+			return
+		}
+
 		claims, err := utils.ValidateToken(tokenString, jwtSecret)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -42,8 +95,158 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		if denylist != nil {
+			denied, err := denylist.IsAccessTokenDenied(claims.ID)
+			if err != nil {
+				utils.Error("Failed to check token denylist: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"success": false,
+					"error":   "Failed to validate token",
+				})
+				c.Abort()
+				return
+			}
+
+			if denied {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"error":   "Token has been revoked",
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
+		c.Set("emailVerified", claims.EmailVerified)
+		c.Set("claims", claims)
+
+		if !setWorkspace(c, workspaces, claims.UserID) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setWorkspace stores the workspace selected by WorkspaceHeader and the
+// user's role in it. It responds with an error and returns false when the
+// user cannot work in that workspace.
+func setWorkspace(c *gin.Context, workspaces WorkspaceResolver, userID int) bool {
+	if workspaces == nil {
+		return true
+	}
+
+	workspaceID := 0
+	if header := c.GetHeader(WorkspaceHeader); header != "" {
+		id, err := strconv.Atoi(header)
+		if err != nil || id < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid " + WorkspaceHeader + " header",
+			})
+			c.Abort()
+			return false
+		}
+		workspaceID = id
+	}
+
+	member, err := workspaces.ResolveWorkspace(userID, workspaceID)
+	if err != nil {
+		if workspaceID != 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Not a member of this workspace",
+			})
+		} else {
+			utils.Error("Failed to resolve workspace of user %d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Failed to resolve workspace",
+			})
+		}
+		c.Abort()
+		return false
+	}
+
+	c.Set("workspaceID", member.WorkspaceID)
+	c.Set("workspaceRole", member.Role)
+
+	return true
+}
+
+// RequireVerifiedEmail restricts accounts with an unverified email address
+// depending on mode: "required" rejects every request, "readonly" only
+// allows safe methods and "off" disables the check. It must run after
+// AuthMiddleware.
+func RequireVerifiedEmail(mode string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if mode == "off" || c.GetBool("emailVerified") {
+			c.Next()
+			return
+		}
+
+		readOnly := c.Request.Method == http.MethodGet ||
+			c.Request.Method == http.MethodHead ||
+			c.Request.Method == http.MethodOptions
+
+		if mode == "readonly" && readOnly {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Email address not verified",
+		})
+		c.Abort()
+	}
+}
+
+// RequireScope limits personal access tokens to their scopes: safe methods
+// need readScope, everything else writeScope. Requests authenticated with a
+// JWT are not restricted.
+func RequireScope(readScope, writeScope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("tokenScopes")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		required := writeScope
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			required = readScope
+		}
+
+		for _, scope := range value.([]string) {
+			if scope == required {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Token is missing the " + required + " scope",
+		})
+		c.Abort()
+	}
+}
+
+// RequireSession rejects requests authenticated with a personal access
+// token, for routes that manage the account itself.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("tokenScopes"); exists {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "This endpoint cannot be used with a personal access token",
+			})
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
@@ -55,4 +258,28 @@ func GetUserID(c *gin.Context) (int, bool) {
 		return 0, false
 	}
 	return userID.(int), true
+}
+
+func GetWorkspaceID(c *gin.Context) (int, bool) {
+	workspaceID, exists := c.Get("workspaceID")
+	if !exists {
+		return 0, false
+	}
+	return workspaceID.(int), true
+}
+
+func GetWorkspaceRole(c *gin.Context) (model.WorkspaceRole, bool) {
+	role, exists := c.Get("workspaceRole")
+	if !exists {
+		return "", false
+	}
+	return role.(model.WorkspaceRole), true
+}
+
+func GetClaims(c *gin.Context) (*utils.Claims, bool) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return nil, false
+	}
+	return claims.(*utils.Claims), true
 }
\ No newline at end of file
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	AuditEventLoginLockout   = "login_lockout"
+	AuditEventLoginIPLockout = "login_ip_lockout"
+)
+
+type AuditLog struct {
+	ID        int           `json:"id" db:"id"`
+	UserID    sql.NullInt64 `json:"user_id" db:"user_id"`
+	Event     string        `json:"event" db:"event"`
+	IPAddress string        `json:"ip_address" db:"ip_address"`
+	Details   string        `json:"details" db:"details"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// LoginFailure counts recent failed logins for one account or one client
+// address.
+type LoginFailure struct {
+	ID            int          `json:"id" db:"id"`
+	Scope         string       `json:"scope" db:"scope"`
+	Key           string       `json:"key" db:"key"`
+	FailureCount  int          `json:"failure_count" db:"failure_count"`
+	LastFailureAt time.Time    `json:"last_failure_at" db:"last_failure_at"`
+	LockedUntil   sql.NullTime `json:"locked_until" db:"locked_until"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Comment is a message in the discussion of a task. MentionIDs are the
+// users mentioned in Body who could see the task when it was written.
+type Comment struct {
+	ID         int       `json:"id" db:"id"`
+	TaskID     int       `json:"task_id" db:"task_id"`
+	UserID     int       `json:"user_id" db:"user_id"`
+	Body       string    `json:"body" db:"body"`
+	MentionIDs []int     `json:"mention_ids" db:"-"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UserIdentity links a local user to an account at an external OpenID
+// Connect provider.
+type UserIdentity struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Provider  string    `json:"provider" db:"provider"`
+	Subject   string    `json:"subject" db:"subject"`
+	Email     string    `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// OIDCState remembers an authorization request between the redirect to the
+// provider and the callback. UserID is set when linking to an existing
+// account or, with Reauth, when the user signs in again to confirm a
+// sensitive change.
+type OIDCState struct {
+	ID           int           `json:"id" db:"id"`
+	StateHash    string        `json:"-" db:"state_hash"`
+	Provider     string        `json:"provider" db:"provider"`
+	Nonce        string        `json:"-" db:"nonce"`
+	CodeVerifier string        `json:"-" db:"code_verifier"`
+	UserID       sql.NullInt64 `json:"user_id" db:"user_id"`
+	Reauth       bool          `json:"reauth" db:"reauth"`
+	ExpiresAt    time.Time     `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+}
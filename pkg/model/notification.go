@@ -0,0 +1,23 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	NotificationTypeReminder   = "reminder"
+	NotificationTypeAssignment = "assignment"
+	NotificationTypeMention    = "mention"
+)
+
+type Notification struct {
+	ID        int           `json:"id" db:"id"`
+	UserID    int           `json:"user_id" db:"user_id"`
+	Type      string        `json:"type" db:"type"`
+	Title     string        `json:"title" db:"title"`
+	Body      string        `json:"body" db:"body"`
+	TaskID    sql.NullInt64 `json:"task_id" db:"task_id"`
+	ReadAt    sql.NullTime  `json:"read_at" db:"read_at"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
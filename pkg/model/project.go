@@ -0,0 +1,19 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Project struct {
+	ID             int          `json:"id" db:"id"`
+	UserID         int          `json:"user_id" db:"user_id"`
+	WorkspaceID    int          `json:"workspace_id" db:"workspace_id"`
+	Name           string       `json:"name" db:"name"`
+	Color          string       `json:"color" db:"color"`
+	ArchivedAt     sql.NullTime `json:"archived_at" db:"archived_at"`
+	OpenTasks      int          `json:"open_tasks" db:"open_tasks"`
+	CompletedTasks int          `json:"completed_tasks" db:"completed_tasks"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
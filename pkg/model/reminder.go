@@ -0,0 +1,43 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ReminderChannel string
+
+const (
+	ReminderChannelEmail   ReminderChannel = "email"
+	ReminderChannelWebhook ReminderChannel = "webhook"
+	ReminderChannelInApp   ReminderChannel = "in_app"
+)
+
+// Reminder fires either at RemindAt or OffsetMinutes before the task's due
+// date. FireAt holds the resolved time and is NULL while an offset reminder
+// has no due date to count from.
+type Reminder struct {
+	ID            int             `json:"id" db:"id"`
+	TaskID        int             `json:"task_id" db:"task_id"`
+	UserID        int             `json:"user_id" db:"user_id"`
+	RemindAt      sql.NullTime    `json:"remind_at" db:"remind_at"`
+	OffsetMinutes sql.NullInt64   `json:"offset_minutes" db:"offset_minutes"`
+	Channel       ReminderChannel `json:"channel" db:"channel"`
+	WebhookURL    string          `json:"webhook_url" db:"webhook_url"`
+	FireAt        sql.NullTime    `json:"fire_at" db:"fire_at"`
+	SentAt        sql.NullTime    `json:"sent_at" db:"sent_at"`
+	FailedAt      sql.NullTime    `json:"failed_at" db:"failed_at"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	LastError     string          `json:"last_error" db:"last_error"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// DueReminder is a reminder picked up by the scheduler together with the
+// task and user details needed to deliver it.
+type DueReminder struct {
+	Reminder
+	TaskTitle string
+	DueDate   sql.NullTime
+	Email     string
+	Name      string
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Role is what a user may do with a task or project. Roles are ordered:
+// each one includes the permissions of the ones before it.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+// Allows reports whether r grants the permissions of required.
+func (r Role) Allows(required Role) bool {
+	return roleRank[r] >= roleRank[required]
+}
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// Share gives a user access to a task or a project owned by someone else.
+// Sharing a task includes its subtasks; sharing a project includes all of
+// its tasks.
+type Share struct {
+	ItemID    int       `json:"item_id" db:"item_id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Email     string    `json:"email" db:"email"`
+	Name      string    `json:"name" db:"name"`
+	Role      Role      `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
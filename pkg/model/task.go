@@ -14,13 +14,41 @@ const (
 )
 
 type Task struct {
-	ID          int          `json:"id" db:"id"`
-	UserID      int          `json:"user_id" db"user_id`
-	Title       string       `json:"title" db:"title"`
-	Description string       `json:"description" db:"description"`
-	IsCompleted bool         `json:"is_completed" db:"is_completed"`
-	Priority    Priority     `json:"priority" db:"priority"`
-	DueDate     sql.NullTime `json:"due_date" db:"due_date"`
-	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+	ID          int           `json:"id" db:"id"`
+	UserID      int           `json:"user_id" db:"user_id"`
+	WorkspaceID int           `json:"workspace_id" db:"workspace_id"`
+	ParentID    sql.NullInt64 `json:"parent_id" db:"parent_id"`
+	ProjectID   sql.NullInt64 `json:"project_id" db:"project_id"`
+	// AssigneeID is the user working on the task; UserID stays its creator.
+	AssigneeID  sql.NullInt64 `json:"assignee_id" db:"assignee_id"`
+	Title       string        `json:"title" db:"title"`
+	Description string        `json:"description" db:"description"`
+	IsCompleted bool          `json:"is_completed" db:"is_completed"`
+	// StatusID is the board column of the task. IsCompleted mirrors the
+	// IsDone flag of that status.
+	StatusID int          `json:"status_id" db:"status_id"`
+	Priority Priority     `json:"priority" db:"priority"`
+	DueDate  sql.NullTime `json:"due_date" db:"due_date"`
+	// RecurrenceRule is an RRULE value; RecurrenceTimezone is the IANA zone
+	// its occurrences are calculated in.
+	RecurrenceRule     sql.NullString `json:"recurrence_rule" db:"recurrence_rule"`
+	RecurrenceTimezone string         `json:"recurrence_timezone" db:"recurrence_timezone"`
+	Position           int64          `json:"position" db:"position"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
+}
+
+// SubtaskProgress counts the direct subtasks of a task.
+type SubtaskProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// TaskBlockers lists the tasks a task depends on. Open counts those that
+// are not completed yet; the task is blocked while it is above zero.
+type TaskBlockers struct {
+	IDs  []int `json:"ids"`
+	Open int   `json:"open"`
 }
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	TaskEventCreated   = "created"
+	TaskEventUpdated   = "updated"
+	TaskEventCompleted = "completed"
+	TaskEventDeleted   = "deleted"
+	TaskEventRestored  = "restored"
+	TaskEventAssigned  = "assigned"
+)
+
+// FieldChange is the old and new value of one task field. Nil stands for
+// an empty (NULL) value.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// TaskEvent is an entry in the append-only history of a task.
+type TaskEvent struct {
+	ID        int64                  `json:"id" db:"id"`
+	TaskID    int                    `json:"task_id" db:"task_id"`
+	ActorID   sql.NullInt64          `json:"actor_id" db:"actor_id"`
+	Action    string                 `json:"action" db:"action"`
+	Changes   map[string]FieldChange `json:"changes" db:"changes"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
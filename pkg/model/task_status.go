@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// TaskStatus is a column of a user's board. Tasks in a status with IsDone
+// set count as completed.
+type TaskStatus struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Position  int       `json:"position" db:"position"`
+	IsDone    bool      `json:"is_done" db:"is_done"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultTaskStatuses are given to every user before they customize their
+// board.
+var DefaultTaskStatuses = []TaskStatus{
+	{Name: "To Do", Position: 0},
+	{Name: "In Progress", Position: 1},
+	{Name: "Blocked", Position: 2},
+	{Name: "In Review", Position: 3},
+	{Name: "Done", Position: 4, IsDone: true},
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type RefreshToken struct {
+	ID        int          `json:"id" db:"id"`
+	UserID    int          `json:"user_id" db:"user_id"`
+	TokenHash string       `json:"-" db:"token_hash"`
+	FamilyID  string       `json:"-" db:"family_id"`
+	ExpiresAt time.Time    `json:"expires_at" db:"expires_at"`
+	RevokedAt sql.NullTime `json:"revoked_at" db:"revoked_at"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        int          `json:"id" db:"id"`
+	UserID    int          `json:"user_id" db:"user_id"`
+	TokenHash string       `json:"-" db:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at" db:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at" db:"used_at"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+type EmailVerificationToken struct {
+	ID        int          `json:"id" db:"id"`
+	UserID    int          `json:"user_id" db:"user_id"`
+	Email     string       `json:"email" db:"email"`
+	TokenHash string       `json:"-" db:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at" db:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at" db:"used_at"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+// PersonalTokenPrefix marks bearer tokens that are personal access tokens
+// rather than JWTs.
+const PersonalTokenPrefix = "ydp_"
+
+const (
+	ScopeTasksRead          = "tasks:read"
+	ScopeTasksWrite         = "tasks:write"
+	ScopeStatusesRead       = "statuses:read"
+	ScopeStatusesWrite      = "statuses:write"
+	ScopeWorkspacesRead     = "workspaces:read"
+	ScopeWorkspacesWrite    = "workspaces:write"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+)
+
+type PersonalAccessToken struct {
+	ID          int          `json:"id" db:"id"`
+	UserID      int          `json:"user_id" db:"user_id"`
+	Name        string       `json:"name" db:"name"`
+	TokenHash   string       `json:"-" db:"token_hash"`
+	TokenPrefix string       `json:"token_prefix" db:"token_prefix"`
+	Scopes      []string     `json:"scopes" db:"scopes"`
+	ExpiresAt   sql.NullTime `json:"expires_at" db:"expires_at"`
+	LastUsedAt  sql.NullTime `json:"last_used_at" db:"last_used_at"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+}
@@ -1,12 +1,17 @@
 package model
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type User struct {
-	ID           int       `json:"id" db:"id"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	Name         string    `json:"name" db:"name"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID              int          `json:"id" db:"id"`
+	Email           string       `json:"email" db:"email"`
+	PasswordHash    string       `json:"-" db:"password_hash"`
+	Name            string       `json:"name" db:"name"`
+	EmailVerifiedAt sql.NullTime `json:"email_verified_at" db:"email_verified_at"`
+	TOTPEnabledAt   sql.NullTime `json:"totp_enabled_at" db:"totp_enabled_at"`
+	CreatedAt       time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at" db:"updated_at"`
 }
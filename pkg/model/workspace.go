@@ -0,0 +1,69 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// WorkspaceRole is what a member may do in a workspace. Like Role, each
+// role includes the permissions of the ones before it.
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleMember WorkspaceRole = "member"
+	WorkspaceRoleAdmin  WorkspaceRole = "admin"
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+)
+
+// Allows reports whether r grants the permissions of required.
+func (r WorkspaceRole) Allows(required WorkspaceRole) bool {
+	return workspaceRoleRank[r] >= workspaceRoleRank[required]
+}
+
+// ItemRole returns the role members with r have on the tasks and projects
+// of the workspace: admins and the owner manage them like their owner,
+// members can edit them.
+func (r WorkspaceRole) ItemRole() Role {
+	if r.Allows(WorkspaceRoleAdmin) {
+		return RoleOwner
+	}
+	return RoleEditor
+}
+
+var workspaceRoleRank = map[WorkspaceRole]int{
+	WorkspaceRoleMember: 1,
+	WorkspaceRoleAdmin:  2,
+	WorkspaceRoleOwner:  3,
+}
+
+// Workspace groups the tasks and projects of a team. Role is the role of
+// the user the workspace was loaded for.
+type Workspace struct {
+	ID        int           `json:"id" db:"id"`
+	Name      string        `json:"name" db:"name"`
+	CreatedBy sql.NullInt64 `json:"created_by" db:"created_by"`
+	Role      WorkspaceRole `json:"role" db:"role"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID int           `json:"workspace_id" db:"workspace_id"`
+	UserID      int           `json:"user_id" db:"user_id"`
+	Email       string        `json:"email" db:"email"`
+	Name        string        `json:"name" db:"name"`
+	Role        WorkspaceRole `json:"role" db:"role"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+}
+
+type WorkspaceInvitation struct {
+	ID          int           `json:"id" db:"id"`
+	WorkspaceID int           `json:"workspace_id" db:"workspace_id"`
+	Email       string        `json:"email" db:"email"`
+	Role        WorkspaceRole `json:"role" db:"role"`
+	TokenHash   string        `json:"-" db:"token_hash"`
+	InvitedBy   sql.NullInt64 `json:"invited_by" db:"invited_by"`
+	ExpiresAt   time.Time     `json:"expires_at" db:"expires_at"`
+	AcceptedAt  sql.NullTime  `json:"accepted_at" db:"accepted_at"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,33 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/mailer"
+)
+
+type EmailNotifier struct {
+	mailer mailer.Mailer
+}
+
+func NewEmailNotifier(mailSender mailer.Mailer) *EmailNotifier {
+	return &EmailNotifier{mailer: mailSender}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, msg *Message) error {
+	if msg.Email == "" {
+		return fmt.Errorf("no e-mail address for user %d", msg.UserID)
+	}
+
+	body := msg.Body
+	if msg.Name != "" {
+		body = fmt.Sprintf("Hi %s,\n\n%s", msg.Name, msg.Body)
+	}
+
+	return n.mailer.Send(&mailer.Message{
+		To:      msg.Email,
+		Subject: msg.Title,
+		Body:    body,
+	})
+}
@@ -0,0 +1,33 @@
+package notifier
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+)
+
+// NotificationStore saves in-app notifications. It is implemented by
+// service.NotificationService.
+type NotificationStore interface {
+	Create(notification *model.Notification) error
+}
+
+// InAppNotifier stores notifications for the user to read in the app.
+type InAppNotifier struct {
+	store NotificationStore
+}
+
+func NewInAppNotifier(store NotificationStore) *InAppNotifier {
+	return &InAppNotifier{store: store}
+}
+
+func (n *InAppNotifier) Notify(ctx context.Context, msg *Message) error {
+	return n.store.Create(&model.Notification{
+		UserID: msg.UserID,
+		Type:   msg.Type,
+		Title:  msg.Title,
+		Body:   msg.Body,
+		TaskID: sql.NullInt64{Int64: int64(msg.TaskID), Valid: msg.TaskID != 0},
+	})
+}
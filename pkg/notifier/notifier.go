@@ -0,0 +1,26 @@
+// Package notifier delivers user notifications such as task reminders over
+// e-mail, webhooks or the in-app notification list.
+package notifier
+
+import (
+	"context"
+	"time"
+)
+
+// Message is a notification for one user. Each Notifier uses the fields it
+// needs: Email for e-mail and WebhookURL for webhooks.
+type Message struct {
+	UserID     int
+	Email      string
+	Name       string
+	Type       string
+	Title      string
+	Body       string
+	TaskID     int
+	WebhookURL string
+	CreatedAt  time.Time
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
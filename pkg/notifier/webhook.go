@@ -0,0 +1,122 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of the request body,
+// prefixed with "sha256=", when a signing secret is configured.
+const SignatureHeader = "X-YouDo-Signature"
+
+type webhookPayload struct {
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	UserID    int       `json:"user_id"`
+	TaskID    int       `json:"task_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookNotifier posts notifications as JSON to the message's WebhookURL.
+// Any response other than 2xx counts as a failed delivery.
+type WebhookNotifier struct {
+	client *http.Client
+	secret string
+}
+
+func NewWebhookNotifier(client *http.Client, secret string) *WebhookNotifier {
+	return &WebhookNotifier{client: client, secret: secret}
+}
+
+// NewWebhookClient returns the HTTP client for webhook deliveries. It checks
+// every address it connects to, including after redirects and DNS changes,
+// and refuses the ones PublicAddress rejects. It never uses a proxy, which
+// would hide the real destination from that check.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !PublicAddress(ip) {
+				return fmt.Errorf("webhook address %s is not allowed", host)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// PublicAddress reports whether webhooks may be sent to ip. Loopback,
+// private, link-local, multicast and unspecified addresses are rejected so
+// reminders cannot reach the server itself or its internal network.
+func PublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, msg *Message) error {
+	if msg.WebhookURL == "" {
+		return fmt.Errorf("no webhook URL for user %d", msg.UserID)
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Type:      msg.Type,
+		Title:     msg.Title,
+		Body:      msg.Body,
+		UserID:    msg.UserID,
+		TaskID:    msg.TaskID,
+		CreatedAt: msg.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
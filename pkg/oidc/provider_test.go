@@ -0,0 +1,245 @@
+package oidc_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/oidc"
+	"github.com/faisal-amiruddin/YouDo/pkg/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID    = "youdo"
+	testRedirectURL = "http://localhost/api/auth/oidc/test/callback"
+	testVerifier    = "verifier-verifier-verifier-verifier"
+)
+
+func newProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	t.Helper()
+
+	server := oidctest.NewServer(testClientID, "secret")
+	t.Cleanup(server.Close)
+
+	return server, oidc.NewProvider(server.ProviderConfig("test", testRedirectURL), nil)
+}
+
+// authorize runs the browser part of the flow and returns the code.
+func authorize(t *testing.T, server *oidctest.Server, provider *oidc.Provider, state, nonce string) string {
+	t.Helper()
+
+	authURL, err := provider.AuthCodeURL(state, nonce, oidc.CodeChallengeS256(testVerifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL failed: %v", err)
+	}
+
+	code, returnedState, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if code == "" {
+		t.Fatal("provider returned no code")
+	}
+	if returnedState != state {
+		t.Fatalf("provider returned state %q, want %q", returnedState, state)
+	}
+
+	return code
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	server, provider := newProvider(t)
+	server.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	code := authorize(t, server, provider, "state", "nonce")
+
+	token, err := provider.Exchange(code, testVerifier)
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+
+	claims, err := provider.VerifyIDToken(token.IDToken, "nonce")
+	if err != nil {
+		t.Fatalf("VerifyIDToken failed: %v", err)
+	}
+
+	if claims.Subject != "alice" || claims.Email != "alice@example.com" || !claims.EmailVerified || claims.Name != "Alice" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	_, provider := newProvider(t)
+
+	authURL, err := provider.AuthCodeURL("state", "nonce", oidc.CodeChallengeS256(testVerifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"state":                 "state",
+		"nonce":                 "nonce",
+		"code_challenge":        oidc.CodeChallengeS256(testVerifier),
+		"code_challenge_method": "S256",
+	}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if !strings.Contains(query.Get("scope"), "openid") {
+		t.Errorf("scope %q does not request openid", query.Get("scope"))
+	}
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// RFC 7636 Appendix B.
+	got := oidc.CodeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("CodeChallengeS256 = %s, want %s", got, want)
+	}
+}
+
+func TestExchangeRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+		reuse    bool
+	}{
+		{name: "wrong PKCE verifier", verifier: "another-verifier-another-verifier"},
+		{name: "missing PKCE verifier", verifier: ""},
+		{name: "reused code", verifier: testVerifier, reuse: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, provider := newProvider(t)
+			code := authorize(t, server, provider, "state", "nonce")
+
+			if tt.reuse {
+				if _, err := provider.Exchange(code, testVerifier); err != nil {
+					t.Fatalf("first Exchange failed: %v", err)
+				}
+			}
+
+			if _, err := provider.Exchange(code, tt.verifier); err == nil {
+				t.Error("Exchange succeeded")
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsWrongClientSecret(t *testing.T) {
+	server, _ := newProvider(t)
+
+	cfg := server.ProviderConfig("test", testRedirectURL)
+	cfg.ClientSecret = "wrong"
+	provider := oidc.NewProvider(cfg, nil)
+
+	code := authorize(t, server, provider, "state", "nonce")
+	if _, err := provider.Exchange(code, testVerifier); err == nil {
+		t.Error("Exchange succeeded with a wrong client secret")
+	}
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	server, provider := newProvider(t)
+	other := oidctest.NewServer(testClientID, "secret")
+	defer other.Close()
+
+	now := time.Now()
+	valid := func() oidc.IDTokenClaims {
+		return oidc.IDTokenClaims{
+			Nonce: "nonce",
+			Email: "alice@example.com",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    server.URL,
+				Subject:   "alice",
+				Audience:  jwt.ClaimStrings{testClientID},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		nonce  string
+		modify func(*oidc.IDTokenClaims)
+		signer *oidctest.Server
+	}{
+		{name: "nonce mismatch", nonce: "other-nonce"},
+		{name: "missing nonce", nonce: "nonce", modify: func(c *oidc.IDTokenClaims) { c.Nonce = "" }},
+		{name: "wrong audience", nonce: "nonce", modify: func(c *oidc.IDTokenClaims) { c.Audience = jwt.ClaimStrings{"someone-else"} }},
+		{name: "wrong issuer", nonce: "nonce", modify: func(c *oidc.IDTokenClaims) { c.Issuer = "https://issuer.example.com" }},
+		{name: "expired", nonce: "nonce", modify: func(c *oidc.IDTokenClaims) {
+			c.IssuedAt = jwt.NewNumericDate(now.Add(-time.Hour))
+			c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute))
+		}},
+		{name: "missing expiry", nonce: "nonce", modify: func(c *oidc.IDTokenClaims) { c.ExpiresAt = nil }},
+		{name: "missing subject", nonce: "nonce", modify: func(c *oidc.IDTokenClaims) { c.Subject = "" }},
+		{name: "signed by another provider", nonce: "nonce", signer: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			if tt.modify != nil {
+				tt.modify(&claims)
+			}
+
+			signer := server
+			if tt.signer != nil {
+				signer = tt.signer
+			}
+
+			idToken, err := signer.SignIDToken(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := provider.VerifyIDToken(idToken, tt.nonce); err == nil {
+				t.Error("VerifyIDToken accepted the token")
+			}
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		idToken, err := server.SignIDToken(valid())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := provider.VerifyIDToken(idToken, "nonce"); err != nil {
+			t.Errorf("VerifyIDToken rejected a valid token: %v", err)
+		}
+	})
+}
+
+func TestVerifyIDTokenRejectsOtherClient(t *testing.T) {
+	server, provider := newProvider(t)
+
+	cfg := server.ProviderConfig("test", testRedirectURL)
+	cfg.ClientID = "another-client"
+	otherClient := oidc.NewProvider(cfg, nil)
+
+	code := authorize(t, server, provider, "state", "nonce")
+	token, err := provider.Exchange(code, testVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := otherClient.VerifyIDToken(token.IDToken, "nonce"); err == nil {
+		t.Error("a token issued to another client was accepted")
+	}
+}
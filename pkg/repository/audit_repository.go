@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(log *model.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (user_id, event, ip_address, details)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		log.UserID,
+		log.Event,
+		log.IPAddress,
+		log.Details,
+	).Scan(&log.ID, &log.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return nil
+}
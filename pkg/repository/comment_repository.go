@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/lib/pq"
+)
+
+type CommentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// Create inserts a comment together with its mentions.
+func (r *CommentRepository) Create(comment *model.Comment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO task_comments (task_id, user_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRow(query, comment.TaskID, comment.UserID, comment.Body).
+		Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	if err := insertMentions(tx, comment.ID, comment.MentionIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a comment of a task with its mentions.
+func (r *CommentRepository) GetByID(id, taskID int) (*model.Comment, error) {
+	comment := &model.Comment{}
+	query := `
+		SELECT id, task_id, user_id, body, created_at, updated_at
+		FROM task_comments
+		WHERE id = $1 AND task_id = $2
+	`
+
+	err := r.db.QueryRow(query, id, taskID).Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&comment.UserID,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("comment not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	mentions, err := r.getMentions([]int{comment.ID})
+	if err != nil {
+		return nil, err
+	}
+	comment.MentionIDs = mentions[comment.ID]
+
+	return comment, nil
+}
+
+// GetByTaskID returns a page of the comments of a task, oldest first, and
+// the total number of comments.
+func (r *CommentRepository) GetByTaskID(taskID, limit, offset int) ([]model.Comment, int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM task_comments WHERE task_id = $1`, taskID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	query := `
+		SELECT id, task_id, user_id, body, created_at, updated_at
+		FROM task_comments
+		WHERE task_id = $1
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []model.Comment{}
+	ids := []int{}
+	for rows.Next() {
+		var comment model.Comment
+		err := rows.Scan(
+			&comment.ID,
+			&comment.TaskID,
+			&comment.UserID,
+			&comment.Body,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+		ids = append(ids, comment.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate comments: %w", err)
+	}
+
+	mentions, err := r.getMentions(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range comments {
+		comments[i].MentionIDs = mentions[comments[i].ID]
+	}
+
+	return comments, total, nil
+}
+
+// Update saves the body of a comment and replaces its mentions.
+func (r *CommentRepository) Update(comment *model.Comment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		`UPDATE task_comments SET body = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND user_id = $3 RETURNING updated_at`,
+		comment.Body, comment.ID, comment.UserID,
+	).Scan(&comment.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("comment not found")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM task_comment_mentions WHERE comment_id = $1`, comment.ID); err != nil {
+		return fmt.Errorf("failed to remove mentions: %w", err)
+	}
+
+	if err := insertMentions(tx, comment.ID, comment.MentionIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a comment written by the user.
+func (r *CommentRepository) Delete(id, taskID, userID int) error {
+	result, err := r.db.Exec(
+		`DELETE FROM task_comments WHERE id = $1 AND task_id = $2 AND user_id = $3`,
+		id, taskID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("comment not found")
+	}
+
+	return nil
+}
+
+// CountByTaskIDs returns the number of comments of each task. Tasks
+// without comments are left out.
+func (r *CommentRepository) CountByTaskIDs(taskIDs []int) (map[int]int, error) {
+	counts := make(map[int]int)
+	if len(taskIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := r.db.Query(
+		`SELECT task_id, COUNT(*) FROM task_comments WHERE task_id = ANY($1) GROUP BY task_id`,
+		pq.Array(taskIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, count int
+		if err := rows.Scan(&taskID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan comment count: %w", err)
+		}
+		counts[taskID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comment counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *CommentRepository) getMentions(commentIDs []int) (map[int][]int, error) {
+	mentions := make(map[int][]int)
+	if len(commentIDs) == 0 {
+		return mentions, nil
+	}
+
+	rows, err := r.db.Query(
+		`SELECT comment_id, user_id FROM task_comment_mentions WHERE comment_id = ANY($1) ORDER BY comment_id, user_id`,
+		pq.Array(commentIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID, userID int
+		if err := rows.Scan(&commentID, &userID); err != nil {
+			return nil, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		mentions[commentID] = append(mentions[commentID], userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate mentions: %w", err)
+	}
+
+	return mentions, nil
+}
+
+func insertMentions(tx *sql.Tx, commentID int, userIDs []int) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(
+		`INSERT INTO task_comment_mentions (comment_id, user_id) SELECT $1, UNNEST($2::int[]) ON CONFLICT DO NOTHING`,
+		commentID, pq.Array(userIDs),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save mentions: %w", err)
+	}
+
+	return nil
+}
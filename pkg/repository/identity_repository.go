@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+)
+
+type IdentityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+func (r *IdentityRepository) Create(identity *model.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+	).Scan(&identity.ID, &identity.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdentityRepository) GetByProviderSubject(provider, subject string) (*model.UserIdentity, error) {
+	identity := &model.UserIdentity{}
+	query := `
+		SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	err := r.db.QueryRow(query, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("identity not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+func (r *IdentityRepository) GetAllByUserID(userID int) ([]model.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY provider
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := []model.UserIdentity{}
+	for rows.Next() {
+		var identity model.UserIdentity
+		err := rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Provider,
+			&identity.Subject,
+			&identity.Email,
+			&identity.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+func (r *IdentityRepository) Delete(userID int, provider string) error {
+	query := `DELETE FROM user_identities WHERE user_id = $1 AND provider = $2`
+
+	result, err := r.db.Exec(query, userID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to unlink identity: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("identity not found")
+	}
+
+	return nil
+}
+
+func (r *IdentityRepository) CreateState(state *model.OIDCState) error {
+	query := `
+		INSERT INTO oidc_states (state_hash, provider, nonce, code_verifier, user_id, reauth, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		state.StateHash,
+		state.Provider,
+		state.Nonce,
+		state.CodeVerifier,
+		state.UserID,
+		state.Reauth,
+		state.ExpiresAt,
+	).Scan(&state.ID, &state.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to store login state: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeState deletes and returns an unexpired state so every
+// authorization response can only be redeemed once.
+func (r *IdentityRepository) ConsumeState(stateHash, provider string) (*model.OIDCState, error) {
+	state := &model.OIDCState{}
+	query := `
+		DELETE FROM oidc_states
+		WHERE state_hash = $1 AND provider = $2 AND expires_at > $3
+		RETURNING id, state_hash, provider, nonce, code_verifier, user_id, reauth, expires_at, created_at
+	`
+
+	err := r.db.QueryRow(query, stateHash, provider, time.Now().UTC()).Scan(
+		&state.ID,
+		&state.StateHash,
+		&state.Provider,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.UserID,
+		&state.Reauth,
+		&state.ExpiresAt,
+		&state.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invalid or expired login state")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login state: %w", err)
+	}
+
+	return state, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type LoginFailureRepository struct {
+	db *sql.DB
+}
+
+func NewLoginFailureRepository(db *sql.DB) *LoginFailureRepository {
+	return &LoginFailureRepository{db: db}
+}
+
+// GetLockedUntil returns when the lock on scope/key ends, or the zero time
+// when there is none.
+func (r *LoginFailureRepository) GetLockedUntil(scope, key string) (time.Time, error) {
+	var lockedUntil sql.NullTime
+	query := `SELECT locked_until FROM login_failures WHERE scope = $1 AND key = $2`
+
+	err := r.db.QueryRow(query, scope, key).Scan(&lockedUntil)
+
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get login failures: %w", err)
+	}
+
+	return lockedUntil.Time, nil
+}
+
+// RecordFailure counts a failed login and returns the number of failures
+// since windowStart. Older failures are forgotten.
+func (r *LoginFailureRepository) RecordFailure(scope, key string, now, windowStart time.Time) (int, error) {
+	var count int
+	query := `
+		INSERT INTO login_failures (scope, key, failure_count, last_failure_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET failure_count = CASE
+				WHEN login_failures.last_failure_at < $4 THEN 1
+				ELSE login_failures.failure_count + 1
+			END,
+			last_failure_at = $3
+		RETURNING failure_count
+	`
+
+	if err := r.db.QueryRow(query, scope, key, now, windowStart).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *LoginFailureRepository) Lock(scope, key string, until time.Time) error {
+	query := `UPDATE login_failures SET locked_until = $1 WHERE scope = $2 AND key = $3`
+
+	if _, err := r.db.Exec(query, until, scope, key); err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LoginFailureRepository) Reset(scope, key string) error {
+	query := `DELETE FROM login_failures WHERE scope = $1 AND key = $2`
+
+	if _, err := r.db.Exec(query, scope, key); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/lib/pq"
+)
+
+type PersonalTokenRepository struct {
+	db *sql.DB
+}
+
+func NewPersonalTokenRepository(db *sql.DB) *PersonalTokenRepository {
+	return &PersonalTokenRepository{db: db}
+}
+
+func (r *PersonalTokenRepository) Create(token *model.PersonalAccessToken) error {
+	query := `
+		INSERT INTO personal_access_tokens (user_id, name, token_hash, token_prefix, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		token.UserID,
+		token.Name,
+		token.TokenHash,
+		token.TokenPrefix,
+		pq.Array(token.Scopes),
+		token.ExpiresAt,
+	).Scan(&token.ID, &token.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create personal access token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PersonalTokenRepository) GetByHash(tokenHash string) (*model.PersonalAccessToken, error) {
+	token := &model.PersonalAccessToken{}
+	query := `
+		SELECT id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, created_at
+		FROM personal_access_tokens
+		WHERE token_hash = $1
+	`
+
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.TokenHash,
+		&token.TokenPrefix,
+		pq.Array(&token.Scopes),
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("personal access token not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *PersonalTokenRepository) GetAllByUserID(userID int) ([]model.PersonalAccessToken, error) {
+	query := `
+		SELECT id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, created_at
+		FROM personal_access_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal access tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []model.PersonalAccessToken{}
+	for rows.Next() {
+		var token model.PersonalAccessToken
+		err := rows.Scan(
+			&token.ID,
+			&token.UserID,
+			&token.Name,
+			&token.TokenHash,
+			&token.TokenPrefix,
+			pq.Array(&token.Scopes),
+			&token.ExpiresAt,
+			&token.LastUsedAt,
+			&token.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan personal access token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// TouchLastUsed records a use of the token. Writes are throttled to one
+// per minute so busy scripts do not update the row on every request.
+func (r *PersonalTokenRepository) TouchLastUsed(id int) error {
+	query := `
+		UPDATE personal_access_tokens
+		SET last_used_at = $1
+		WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3)
+	`
+
+	now := time.Now().UTC()
+	if _, err := r.db.Exec(query, now, id, now.Add(-time.Minute)); err != nil {
+		return fmt.Errorf("failed to update token usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PersonalTokenRepository) Delete(id int, userID int) error {
+	query := `DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete personal access token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("personal access token not found")
+	}
+
+	return nil
+}
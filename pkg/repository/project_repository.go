@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+)
+
+type ProjectRepository struct {
+	db *sql.DB
+}
+
+func NewProjectRepository(db *sql.DB) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+const projectColumns = `
+	p.id, p.user_id, p.workspace_id, p.name, p.color, p.archived_at,
+	(SELECT COUNT(*) FROM tasks t WHERE t.project_id = p.id AND t.is_completed = false AND t.deleted_at IS NULL),
+	(SELECT COUNT(*) FROM tasks t WHERE t.project_id = p.id AND t.is_completed = true AND t.deleted_at IS NULL),
+	p.created_at, p.updated_at
+`
+
+func scanProject(row rowScanner, project *model.Project) error {
+	return row.Scan(
+		&project.ID,
+		&project.UserID,
+		&project.WorkspaceID,
+		&project.Name,
+		&project.Color,
+		&project.ArchivedAt,
+		&project.OpenTasks,
+		&project.CompletedTasks,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	)
+}
+
+func (r *ProjectRepository) Create(project *model.Project) error {
+	query := `
+		INSERT INTO projects (user_id, workspace_id, name, color)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(query, project.UserID, project.WorkspaceID, project.Name, project.Color).Scan(
+		&project.ID,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) GetByID(id int, userID int) (*model.Project, error) {
+	project := &model.Project{}
+	query := `SELECT ` + projectColumns + ` FROM projects p WHERE p.id = $1 AND p.user_id = $2`
+
+	err := scanProject(r.db.QueryRow(query, id, userID), project)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+// GetAllByWorkspace returns the projects of a workspace and the projects of
+// any workspace shared with the user.
+func (r *ProjectRepository) GetAllByWorkspace(workspaceID, userID int, includeArchived bool) ([]model.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects p
+		WHERE (p.workspace_id = $1 OR p.id IN (SELECT project_id FROM project_shares WHERE user_id = $2))
+			AND ($3 OR p.archived_at IS NULL)
+		ORDER BY p.archived_at IS NOT NULL, p.name, p.id
+	`
+
+	rows, err := r.db.Query(query, workspaceID, userID, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []model.Project{}
+	for rows.Next() {
+		var project model.Project
+		if err := scanProject(rows, &project); err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+func (r *ProjectRepository) Update(project *model.Project) error {
+	query := `
+		UPDATE projects
+		SET name = $1, color = $2, archived_at = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND user_id = $5
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		project.Name,
+		project.Color,
+		project.ArchivedAt,
+		project.ID,
+		project.UserID,
+	).Scan(&project.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("project not found")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a project. Its tasks are deleted along with it when
+// deleteTasks is set and moved to the inbox otherwise.
+func (r *ProjectRepository) Delete(id int, userID int, deleteTasks bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if deleteTasks {
+		_, err = tx.Exec(`DELETE FROM tasks WHERE project_id = $1 AND user_id = $2`, id, userID)
+	} else {
+		_, err = tx.Exec(
+			`UPDATE tasks SET project_id = NULL, updated_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND user_id = $2`,
+			id, userID,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to clear project tasks: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM projects WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("project not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+)
+
+type ReminderRepository struct {
+	db *sql.DB
+}
+
+func NewReminderRepository(db *sql.DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+const reminderColumns = `
+	r.id, r.task_id, r.user_id, r.remind_at, r.offset_minutes, r.channel, r.webhook_url,
+	r.fire_at, r.sent_at, r.failed_at, r.attempts, r.last_error, r.created_at
+`
+
+func reminderFields(reminder *model.Reminder) []interface{} {
+	return []interface{}{
+		&reminder.ID,
+		&reminder.TaskID,
+		&reminder.UserID,
+		&reminder.RemindAt,
+		&reminder.OffsetMinutes,
+		&reminder.Channel,
+		&reminder.WebhookURL,
+		&reminder.FireAt,
+		&reminder.SentAt,
+		&reminder.FailedAt,
+		&reminder.Attempts,
+		&reminder.LastError,
+		&reminder.CreatedAt,
+	}
+}
+
+func (r *ReminderRepository) Create(reminder *model.Reminder) error {
+	query := `
+		INSERT INTO task_reminders (task_id, user_id, remind_at, offset_minutes, channel, webhook_url, fire_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		reminder.TaskID,
+		reminder.UserID,
+		reminder.RemindAt,
+		reminder.OffsetMinutes,
+		reminder.Channel,
+		reminder.WebhookURL,
+		reminder.FireAt,
+	).Scan(&reminder.ID, &reminder.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ReminderRepository) GetAllByTaskID(taskID int, userID int) ([]model.Reminder, error) {
+	query := `
+		SELECT ` + reminderColumns + `
+		FROM task_reminders r
+		WHERE r.task_id = $1 AND r.user_id = $2
+		ORDER BY r.fire_at NULLS LAST, r.id
+	`
+
+	rows, err := r.db.Query(query, taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := []model.Reminder{}
+	for rows.Next() {
+		var reminder model.Reminder
+		if err := rows.Scan(reminderFields(&reminder)...); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
+
+func (r *ReminderRepository) Delete(id int, taskID int, userID int) error {
+	result, err := r.db.Exec(
+		`DELETE FROM task_reminders WHERE id = $1 AND task_id = $2 AND user_id = $3`,
+		id, taskID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("reminder not found")
+	}
+
+	return nil
+}
+
+// rescheduleReminders moves the pending offset reminders of a task after
+// its due date changed. Without a due date they stay dormant.
+func rescheduleReminders(tx *sql.Tx, taskID int, dueDate sql.NullTime) error {
+	query := `
+		UPDATE task_reminders
+		SET fire_at = $2::timestamp - offset_minutes * INTERVAL '1 minute', attempts = 0, last_error = ''
+		WHERE task_id = $1 AND offset_minutes IS NOT NULL AND sent_at IS NULL AND failed_at IS NULL
+	`
+
+	if _, err := tx.Exec(query, taskID, dueDate); err != nil {
+		return fmt.Errorf("failed to reschedule reminders: %w", err)
+	}
+
+	return nil
+}
+
+// copyReminderOffsets gives a task the offset reminders of another task,
+// e.g. for the next occurrence of a recurring task.
+func copyReminderOffsets(tx *sql.Tx, fromTaskID, toTaskID int, dueDate sql.NullTime) error {
+	query := `
+		INSERT INTO task_reminders (task_id, user_id, offset_minutes, channel, webhook_url, fire_at)
+		SELECT $2, user_id, offset_minutes, channel, webhook_url, $3::timestamp - offset_minutes * INTERVAL '1 minute'
+		FROM task_reminders
+		WHERE task_id = $1 AND offset_minutes IS NOT NULL
+	`
+
+	if _, err := tx.Exec(query, fromTaskID, toTaskID, dueDate); err != nil {
+		return fmt.Errorf("failed to copy reminders: %w", err)
+	}
+
+	return nil
+}
+
+// ProcessNext locks one pending reminder that is due at now, passes it to
+// handle and saves the delivery state handle leaves on it. The row stays
+// locked with FOR UPDATE SKIP LOCKED until then, so other instances skip it
+// instead of firing it twice. It reports false when nothing is due.
+func (r *ReminderRepository) ProcessNext(now time.Time, handle func(reminder *model.DueReminder)) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ` + reminderColumns + `, t.title, t.due_date, u.email, u.name
+		FROM task_reminders r
+		JOIN tasks t ON t.id = r.task_id
+		JOIN users u ON u.id = r.user_id
+		WHERE r.sent_at IS NULL AND r.failed_at IS NULL AND r.fire_at <= $1 AND t.is_completed = false AND t.deleted_at IS NULL
+		ORDER BY r.fire_at
+		LIMIT 1
+		FOR UPDATE OF r SKIP LOCKED
+	`
+
+	var reminder model.DueReminder
+	fields := append(reminderFields(&reminder.Reminder), &reminder.TaskTitle, &reminder.DueDate, &reminder.Email, &reminder.Name)
+
+	err = tx.QueryRow(query, now).Scan(fields...)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to get due reminder: %w", err)
+	}
+
+	handle(&reminder)
+
+	_, err = tx.Exec(
+		`UPDATE task_reminders SET fire_at = $1, sent_at = $2, failed_at = $3, attempts = $4, last_error = $5 WHERE id = $6`,
+		reminder.FireAt,
+		reminder.SentAt,
+		reminder.FailedAt,
+		reminder.Attempts,
+		reminder.LastError,
+		reminder.ID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update reminder: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/lib/pq"
+)
+
+type ShareRepository struct {
+	db *sql.DB
+}
+
+func NewShareRepository(db *sql.DB) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+// sharedTaskIDs selects the IDs of the tasks shared with the user in $1,
+// directly, through one of their ancestors or through their project.
+var sharedTaskIDs = fmt.Sprintf(`
+	WITH RECURSIVE shared AS (
+		SELECT t.id, 1 AS depth FROM tasks t
+		WHERE t.id IN (SELECT task_id FROM task_shares WHERE user_id = $1)
+			OR t.project_id IN (SELECT project_id FROM project_shares WHERE user_id = $1)
+		UNION
+		SELECT c.id, s.depth + 1 FROM tasks c JOIN shared s ON c.parent_id = s.id
+		WHERE s.depth < %d
+	)
+	SELECT id FROM shared
+`, MaxTaskDepth)
+
+// GetTaskAccess returns the owner of a task and the role the user has on
+// it. Shares of the task's ancestors and of their projects count as well,
+// and so does membership of the task's workspace.
+func (r *ShareRepository) GetTaskAccess(taskID, userID int) (int, model.Role, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_id, project_id, user_id, workspace_id, 1 AS depth FROM tasks WHERE id = $1
+			UNION ALL
+			SELECT t.id, t.parent_id, t.project_id, t.user_id, t.workspace_id, c.depth + 1
+			FROM tasks t JOIN chain c ON t.id = c.parent_id
+			WHERE c.depth < $3
+		)
+		SELECT c.user_id, (
+			SELECT s.role FROM (
+				SELECT role FROM task_shares WHERE user_id = $2 AND task_id IN (SELECT id FROM chain)
+				UNION ALL
+				SELECT role FROM project_shares WHERE user_id = $2 AND project_id IN (SELECT project_id FROM chain)
+			) s
+			ORDER BY s.role = 'editor' DESC
+			LIMIT 1
+		), (SELECT role FROM workspace_members WHERE workspace_id = c.workspace_id AND user_id = $2)
+		FROM chain c
+		WHERE c.depth = 1
+	`
+
+	var ownerID int
+	var role, workspaceRole sql.NullString
+	err := r.db.QueryRow(query, taskID, userID, MaxTaskDepth).Scan(&ownerID, &role, &workspaceRole)
+
+	if err == sql.ErrNoRows {
+		return 0, "", fmt.Errorf("task not found")
+	}
+
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get task access: %w", err)
+	}
+
+	return accessRole(ownerID, userID, role, workspaceRole, "task")
+}
+
+// TaskAccess is the owner of a task and the role a user has on it.
+type TaskAccess struct {
+	OwnerID int
+	Role    model.Role
+}
+
+// GetTasksAccess does what GetTaskAccess does for many tasks in one query.
+// Tasks that are missing or that the user has no access to are left out.
+func (r *ShareRepository) GetTasksAccess(taskIDs []int, userID int) (map[int]TaskAccess, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id AS task_id, id, parent_id, project_id, 1 AS depth FROM tasks WHERE id = ANY($1)
+			UNION ALL
+			SELECT c.task_id, t.id, t.parent_id, t.project_id, c.depth + 1
+			FROM tasks t JOIN chain c ON t.id = c.parent_id
+			WHERE c.depth < $3
+		)
+		SELECT t.id, t.user_id, (
+			SELECT s.role FROM (
+				SELECT role FROM task_shares
+				WHERE user_id = $2 AND task_id IN (SELECT id FROM chain WHERE chain.task_id = t.id)
+				UNION ALL
+				SELECT role FROM project_shares
+				WHERE user_id = $2 AND project_id IN (SELECT project_id FROM chain WHERE chain.task_id = t.id)
+			) s
+			ORDER BY s.role = 'editor' DESC
+			LIMIT 1
+		), (SELECT role FROM workspace_members WHERE workspace_id = t.workspace_id AND user_id = $2)
+		FROM tasks t
+		WHERE t.id = ANY($1)
+	`
+
+	rows, err := r.db.Query(query, pq.Array(taskIDs), userID, MaxTaskDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task access: %w", err)
+	}
+	defer rows.Close()
+
+	access := make(map[int]TaskAccess, len(taskIDs))
+	for rows.Next() {
+		var taskID, ownerID int
+		var role, workspaceRole sql.NullString
+		if err := rows.Scan(&taskID, &ownerID, &role, &workspaceRole); err != nil {
+			return nil, fmt.Errorf("failed to scan task access: %w", err)
+		}
+
+		ownerID, best, err := accessRole(ownerID, userID, role, workspaceRole, "task")
+		if err != nil {
+			continue
+		}
+		access[taskID] = TaskAccess{OwnerID: ownerID, Role: best}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get task access: %w", err)
+	}
+
+	return access, nil
+}
+
+// GetProjectAccess returns the owner of a project and the role the user
+// has on it through a share or membership of its workspace.
+func (r *ShareRepository) GetProjectAccess(projectID, userID int) (int, model.Role, error) {
+	query := `
+		SELECT p.user_id,
+			(SELECT role FROM project_shares WHERE project_id = p.id AND user_id = $2),
+			(SELECT role FROM workspace_members WHERE workspace_id = p.workspace_id AND user_id = $2)
+		FROM projects p
+		WHERE p.id = $1
+	`
+
+	var ownerID int
+	var role, workspaceRole sql.NullString
+	err := r.db.QueryRow(query, projectID, userID).Scan(&ownerID, &role, &workspaceRole)
+
+	if err == sql.ErrNoRows {
+		return 0, "", fmt.Errorf("project not found")
+	}
+
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get project access: %w", err)
+	}
+
+	return accessRole(ownerID, userID, role, workspaceRole, "project")
+}
+
+// accessRole turns the owner, share and workspace membership of an item
+// into the user's role, taking the highest one. Items the user has no
+// access to are reported as not found.
+func accessRole(ownerID, userID int, role, workspaceRole sql.NullString, item string) (int, model.Role, error) {
+	if ownerID == userID {
+		return ownerID, model.RoleOwner, nil
+	}
+
+	var best model.Role
+	if role.Valid {
+		best = model.Role(role.String)
+	}
+	if workspaceRole.Valid {
+		if memberRole := model.WorkspaceRole(workspaceRole.String).ItemRole(); !best.Allows(memberRole) {
+			best = memberRole
+		}
+	}
+
+	if best == "" {
+		return 0, "", fmt.Errorf("%s not found", item)
+	}
+
+	return ownerID, best, nil
+}
+
+// ShareTask gives a user a role on a task, replacing any role they had.
+func (r *ShareRepository) ShareTask(taskID, userID int, role model.Role, sharedBy int) error {
+	return r.share("task_shares", "task_id", taskID, userID, role, sharedBy)
+}
+
+func (r *ShareRepository) UnshareTask(taskID, userID int) error {
+	return r.unshare("task_shares", "task_id", taskID, userID)
+}
+
+func (r *ShareRepository) GetTaskShares(taskID int) ([]model.Share, error) {
+	return r.getShares("task_shares", "task_id", taskID)
+}
+
+// ShareProject gives a user a role on a project, replacing any role they
+// had.
+func (r *ShareRepository) ShareProject(projectID, userID int, role model.Role, sharedBy int) error {
+	return r.share("project_shares", "project_id", projectID, userID, role, sharedBy)
+}
+
+func (r *ShareRepository) UnshareProject(projectID, userID int) error {
+	return r.unshare("project_shares", "project_id", projectID, userID)
+}
+
+func (r *ShareRepository) GetProjectShares(projectID int) ([]model.Share, error) {
+	return r.getShares("project_shares", "project_id", projectID)
+}
+
+func (r *ShareRepository) share(table, column string, itemID, userID int, role model.Role, sharedBy int) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (%[2]s, user_id, role, shared_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (%[2]s, user_id) DO UPDATE SET role = EXCLUDED.role
+	`, table, column)
+
+	if _, err := r.db.Exec(query, itemID, userID, role, sharedBy); err != nil {
+		return fmt.Errorf("failed to share: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ShareRepository) unshare(table, column string, itemID, userID int) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND user_id = $2`, table, column)
+
+	result, err := r.db.Exec(query, itemID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove share: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("share not found")
+	}
+
+	return nil
+}
+
+func (r *ShareRepository) getShares(table, column string, itemID int) ([]model.Share, error) {
+	query := fmt.Sprintf(`
+		SELECT s.%[2]s, s.user_id, u.email, u.name, s.role, s.created_at
+		FROM %[1]s s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.%[2]s = $1
+		ORDER BY s.created_at, s.user_id
+	`, table, column)
+
+	rows, err := r.db.Query(query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []model.Share{}
+	for rows.Next() {
+		var share model.Share
+		err := rows.Scan(&share.ItemID, &share.UserID, &share.Email, &share.Name, &share.Role, &share.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan share: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
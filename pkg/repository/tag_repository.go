@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/lib/pq"
+)
+
+type TagRepository struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) Create(tag *model.Tag) error {
+	query := `
+		INSERT INTO tags (user_id, name, color)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(query, tag.UserID, tag.Name, tag.Color).Scan(&tag.ID, &tag.CreatedAt, &tag.UpdatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("tag already exists")
+		}
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TagRepository) GetByID(id int, userID int) (*model.Tag, error) {
+	tag := &model.Tag{}
+	query := `
+		SELECT id, user_id, name, color, created_at, updated_at
+		FROM tags
+		WHERE id = $1 AND user_id = $2
+	`
+
+	err := r.db.QueryRow(query, id, userID).Scan(
+		&tag.ID,
+		&tag.UserID,
+		&tag.Name,
+		&tag.Color,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tag not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+func (r *TagRepository) GetAllByUserID(userID int) ([]model.Tag, error) {
+	query := `
+		SELECT id, user_id, name, color, created_at, updated_at
+		FROM tags
+		WHERE user_id = $1
+		ORDER BY name
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		var tag model.Tag
+		err := rows.Scan(
+			&tag.ID,
+			&tag.UserID,
+			&tag.Name,
+			&tag.Color,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepository) Update(tag *model.Tag) error {
+	query := `
+		UPDATE tags
+		SET name = $1, color = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND user_id = $4
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(query, tag.Name, tag.Color, tag.ID, tag.UserID).Scan(&tag.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("tag not found")
+	}
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("tag already exists")
+		}
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TagRepository) Delete(id int, userID int) error {
+	query := `DELETE FROM tags WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag not found")
+	}
+
+	return nil
+}
+
+// CountOwned returns how many of the given tags belong to the user.
+func (r *TagRepository) CountOwned(ids []int, userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM tags WHERE id = ANY($1) AND user_id = $2`
+
+	if err := r.db.QueryRow(query, pq.Array(ids), userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tags: %w", err)
+	}
+
+	return count, nil
+}
+
+// setTaskTags replaces the tags of a task with those of tagIDs owned by
+// userID.
+func setTaskTags(tx *sql.Tx, taskID int, tagIDs []int, userID int) error {
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = $1`, taskID); err != nil {
+		return fmt.Errorf("failed to clear task tags: %w", err)
+	}
+
+	if len(tagIDs) > 0 {
+		query := `
+			INSERT INTO task_tags (task_id, tag_id)
+			SELECT $1, id FROM tags WHERE id = ANY($2) AND user_id = $3
+		`
+		if _, err := tx.Exec(query, taskID, pq.Array(tagIDs), userID); err != nil {
+			return fmt.Errorf("failed to set task tags: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// copyTaskTags gives a task the tags of another task.
+func copyTaskTags(tx *sql.Tx, fromTaskID, toTaskID int) error {
+	query := `
+		INSERT INTO task_tags (task_id, tag_id)
+		SELECT $2, tag_id FROM task_tags WHERE task_id = $1
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := tx.Exec(query, fromTaskID, toTaskID); err != nil {
+		return fmt.Errorf("failed to copy task tags: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTaskIDs loads the tags of many tasks in one query, keyed by task ID.
+func (r *TagRepository) GetByTaskIDs(taskIDs []int) (map[int][]model.Tag, error) {
+	tags := make(map[int][]model.Tag)
+	if len(taskIDs) == 0 {
+		return tags, nil
+	}
+
+	query := `
+		SELECT tt.task_id, t.id, t.user_id, t.name, t.color, t.created_at, t.updated_at
+		FROM task_tags tt
+		JOIN tags t ON t.id = tt.tag_id
+		WHERE tt.task_id = ANY($1)
+		ORDER BY t.name
+	`
+
+	rows, err := r.db.Query(query, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var tag model.Tag
+		err := rows.Scan(
+			&taskID,
+			&tag.ID,
+			&tag.UserID,
+			&tag.Name,
+			&tag.Color,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task tag: %w", err)
+		}
+		tags[taskID] = append(tags[taskID], tag)
+	}
+
+	return tags, nil
+}
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
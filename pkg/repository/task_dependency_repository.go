@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/lib/pq"
+)
+
+// TaskDependencyRepository stores which tasks block which. A task can
+// only be started once all of its blockers are completed.
+type TaskDependencyRepository struct {
+	db *sql.DB
+}
+
+func NewTaskDependencyRepository(db *sql.DB) *TaskDependencyRepository {
+	return &TaskDependencyRepository{db: db}
+}
+
+// Add makes blockerID a blocker of taskID. Both tasks must be in the same
+// workspace, and the dependency is rejected when blockerID already depends
+// on taskID, directly or through other tasks. Callers check that the user
+// may edit taskID and see blockerID.
+func (r *TaskDependencyRepository) Add(taskID, blockerID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	workspaceID, err := taskWorkspace(tx, taskID)
+	if err != nil {
+		return err
+	}
+
+	blockerWorkspaceID, err := taskWorkspace(tx, blockerID)
+	if err != nil {
+		return err
+	}
+
+	if blockerWorkspaceID != workspaceID {
+		return fmt.Errorf("a task can only be blocked by tasks in the same workspace")
+	}
+
+	// Serialize changes in one workspace so two dependencies added at the
+	// same time cannot close a cycle together.
+	if err := lockWorkspace(tx, workspaceID); err != nil {
+		return err
+	}
+
+	query := `
+		WITH RECURSIVE blockers AS (
+			SELECT blocker_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.blocker_id FROM task_dependencies d JOIN blockers b ON d.task_id = b.blocker_id
+		)
+		SELECT EXISTS (SELECT 1 FROM blockers WHERE blocker_id = $2)
+	`
+
+	var cycle bool
+	if err := tx.QueryRow(query, blockerID, taskID).Scan(&cycle); err != nil {
+		return fmt.Errorf("failed to check dependency cycle: %w", err)
+	}
+
+	if cycle {
+		return fmt.Errorf("dependency would create a cycle")
+	}
+
+	_, err = tx.Exec(`INSERT INTO task_dependencies (task_id, blocker_id) VALUES ($1, $2)`, taskID, blockerID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("dependency already exists")
+		}
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// taskWorkspace returns the workspace of a task that is not in the trash.
+func taskWorkspace(tx *sql.Tx, taskID int) (int, error) {
+	var workspaceID int
+	err := tx.QueryRow(`SELECT workspace_id FROM tasks WHERE id = $1 AND deleted_at IS NULL`, taskID).Scan(&workspaceID)
+
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("task not found")
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return workspaceID, nil
+}
+
+func (r *TaskDependencyRepository) Remove(taskID, blockerID, userID int) error {
+	query := `
+		DELETE FROM task_dependencies d
+		USING tasks t
+		WHERE d.task_id = $1 AND d.blocker_id = $2 AND t.id = d.task_id AND t.user_id = $3
+	`
+
+	result, err := r.db.Exec(query, taskID, blockerID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("dependency not found")
+	}
+
+	return nil
+}
+
+// GetBlockers returns the blockers of the given tasks keyed by task ID.
+// Blockers in the trash are left out.
+func (r *TaskDependencyRepository) GetBlockers(taskIDs []int) (map[int]model.TaskBlockers, error) {
+	blockers := make(map[int]model.TaskBlockers)
+	if len(taskIDs) == 0 {
+		return blockers, nil
+	}
+
+	query := `
+		SELECT d.task_id, d.blocker_id, b.is_completed
+		FROM task_dependencies d
+		JOIN tasks b ON b.id = d.blocker_id
+		WHERE d.task_id = ANY($1) AND b.deleted_at IS NULL
+		ORDER BY d.task_id, d.blocker_id
+	`
+
+	rows, err := r.db.Query(query, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, blockerID int
+		var completed bool
+		if err := rows.Scan(&taskID, &blockerID, &completed); err != nil {
+			return nil, fmt.Errorf("failed to scan blocker: %w", err)
+		}
+
+		b := blockers[taskID]
+		b.IDs = append(b.IDs, blockerID)
+		if !completed {
+			b.Open++
+		}
+		blockers[taskID] = b
+	}
+
+	return blockers, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+)
+
+type TaskEventRepository struct {
+	db *sql.DB
+}
+
+func NewTaskEventRepository(db *sql.DB) *TaskEventRepository {
+	return &TaskEventRepository{db: db}
+}
+
+// GetByTaskID returns the history of a task, newest first.
+func (r *TaskEventRepository) GetByTaskID(taskID int) ([]model.TaskEvent, error) {
+	query := `
+		SELECT id, task_id, actor_id, action, changes, created_at
+		FROM task_events
+		WHERE task_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+	defer rows.Close()
+
+	events := []model.TaskEvent{}
+	for rows.Next() {
+		var event model.TaskEvent
+		var changes []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.TaskID,
+			&event.ActorID,
+			&event.Action,
+			&changes,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task event: %w", err)
+		}
+
+		if err := json.Unmarshal(changes, &event.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode task event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// insertTaskEvent appends an event inside the transaction that makes the
+// change it describes.
+func insertTaskEvent(tx *sql.Tx, event *model.TaskEvent) error {
+	changes := event.Changes
+	if changes == nil {
+		changes = map[string]model.FieldChange{}
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode task event: %w", err)
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO task_events (task_id, actor_id, action, changes) VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		event.TaskID,
+		event.ActorID,
+		event.Action,
+		data,
+	).Scan(&event.ID, &event.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to write task event: %w", err)
+	}
+
+	return nil
+}
@@ -3,60 +3,144 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/lib/pq"
 )
 
 type TaskRepository struct {
 	db *sql.DB
 }
 
-func NewTaskRepository(db *sql.DB) *TaskRepository {
-	return &TaskRepository{db: db}
+// TaskFilter narrows down and orders the tasks returned by List.
+// Nil or empty fields are ignored.
+type TaskFilter struct {
+	IsCompleted *bool
+	StatusID    int
+	Priority    model.Priority
+	// TopLevel leaves out subtasks.
+	TopLevel bool
+	// Actionable keeps open tasks whose blockers are all completed.
+	Actionable bool
+	// WorkspaceID keeps the tasks of one workspace.
+	WorkspaceID int
+	// IncludeShared adds the tasks other users shared with the user. With
+	// WorkspaceID it selects every task of the workspace instead of only
+	// the user's own, and the shared tasks of any workspace.
+	IncludeShared bool
+	// AssigneeID keeps the tasks assigned to one user; Unassigned keeps
+	// those without an assignee.
+	AssigneeID int
+	Unassigned bool
+	// ProjectID keeps the tasks of one project; zero selects tasks without
+	// a project (the inbox).
+	ProjectID   *int
+	DueFrom     *time.Time
+	DueTo       *time.Time
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	// TagIDs keeps tasks carrying any of the tags, or all of them when
+	// MatchAllTags is set.
+	TagIDs       []int
+	MatchAllTags bool
+	SortBy       string
+	Order        string
+	Limit        int
+	Offset       int
 }
 
-func (r *TaskRepository) Create(task *model.Task) error {
-	query := `
-		INSERT INTO tasks (user_id, title, description, priority, due_date)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, is_completed, created_at, updated_at
-	`
+var taskSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"due_date":   "due_date",
+	"title":      "title",
+	"priority":   "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 END",
+	"position":   "position",
+}
 
-	err := r.db.QueryRow(
-		query,
-		task.UserID,
-		task.Title,
-		task.Description,
-		task.Priority,
-		task.DueDate,
-	).Scan(&task.ID, &task.IsCompleted, &task.CreatedAt, &task.UpdatedAt)
+// TaskPositionGap is the distance between the positions of neighbouring
+// tasks after they are appended or rebalanced. Moving a task takes the
+// middle of the gap, so about ten moves fit into one spot before the
+// workspace's tasks have to be renumbered.
+const TaskPositionGap = 1024
 
-	if err != nil {
-		return fmt.Errorf("failed to create task: %w", err)
-	}
+// MaxTaskDepth is the number of levels a task hierarchy may have, counting
+// the top-level task.
+const MaxTaskDepth = 5
 
-	return nil
-}
+const taskColumns = `id, user_id, workspace_id, parent_id, project_id, assignee_id, title, description, is_completed, status_id, priority, due_date, recurrence_rule, recurrence_timezone, position, created_at, updated_at, deleted_at`
 
-func (r *TaskRepository) GetByID(id int, userID int) (*model.Task, error) {
-	task := &model.Task{}
-	query := `
-		SELECT id, user_id, title, description, is_completed, priority, due_date, created_at, updated_at
-		FROM tasks
-		WHERE id = $1 AND user_id = $2
-	`
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	err := r.db.QueryRow(query, id, userID).Scan(
+func scanTask(row rowScanner, task *model.Task) error {
+	return row.Scan(
 		&task.ID,
 		&task.UserID,
+		&task.WorkspaceID,
+		&task.ParentID,
+		&task.ProjectID,
+		&task.AssigneeID,
 		&task.Title,
 		&task.Description,
 		&task.IsCompleted,
+		&task.StatusID,
 		&task.Priority,
 		&task.DueDate,
+		&task.RecurrenceRule,
+		&task.RecurrenceTimezone,
+		&task.Position,
 		&task.CreatedAt,
 		&task.UpdatedAt,
+		&task.DeletedAt,
 	)
+}
+
+func NewTaskRepository(db *sql.DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+// Create inserts a task with those of tagIDs that belong to its owner and
+// records event for it in one transaction. A subtask's parent must belong to
+// the same user and the hierarchy may not grow deeper than MaxTaskDepth.
+func (r *TaskRepository) Create(task *model.Task, event *model.TaskEvent, tagIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertTask(tx, task, event); err != nil {
+		return err
+	}
+
+	if len(tagIDs) > 0 {
+		if err := setTaskTags(tx, task.ID, tagIDs, task.UserID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) GetByID(id int, userID int) (*model.Task, error) {
+	task := &model.Task{}
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	err := scanTask(r.db.QueryRow(query, id, userID), task)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("task not found")
@@ -71,9 +155,9 @@ func (r *TaskRepository) GetByID(id int, userID int) (*model.Task, error) {
 
 func (r *TaskRepository) GetAllByUserID(userID int) ([]model.Task, error) {
 	query := `
-		SELECT id, user_id, title, description, is_completed, priority, due_date, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -86,17 +170,7 @@ func (r *TaskRepository) GetAllByUserID(userID int) ([]model.Task, error) {
 	tasks := []model.Task{}
 	for rows.Next() {
 		var task model.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.UserID,
-			&task.Title,
-			&task.Description,
-			&task.IsCompleted,
-			&task.Priority,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
+		err := scanTask(rows, &task)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
@@ -106,64 +180,1104 @@ func (r *TaskRepository) GetAllByUserID(userID int) ([]model.Task, error) {
 	return tasks, nil
 }
 
-func (r *TaskRepository) Update(task *model.Task) error {
-	query := `
-		UPDATE tasks
-		SET title = $1, description = $2, is_completed = $3, priority = $4, due_date = $5, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $6 AND user_id = $7
-		RETURNING updated_at
-	`
+func (r *TaskRepository) List(userID int, filter *TaskFilter) ([]model.Task, int, error) {
+	conditions := []string{"user_id = $1", "deleted_at IS NULL"}
+	args := []interface{}{userID}
 
-	err := r.db.QueryRow(
-		query,
-		task.Title,
-		task.Description,
-		task.IsCompleted,
-		task.Priority,
-		task.DueDate,
-		task.ID,
-		task.UserID,
-	).Scan(&task.UpdatedAt)
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	switch {
+	case filter.WorkspaceID != 0 && filter.IncludeShared:
+		args = append(args, filter.WorkspaceID)
+		conditions[0] = fmt.Sprintf("(workspace_id = $%d OR id IN (%s))", len(args), sharedTaskIDs)
+	case filter.WorkspaceID != 0:
+		addCondition("workspace_id = $%d", filter.WorkspaceID)
+	case filter.IncludeShared:
+		conditions[0] = "(user_id = $1 OR id IN (" + sharedTaskIDs + "))"
+	}
+
+	if filter.IsCompleted != nil {
+		addCondition("is_completed = $%d", *filter.IsCompleted)
+	}
+	if filter.StatusID != 0 {
+		addCondition("status_id = $%d", filter.StatusID)
+	}
+	if filter.TopLevel {
+		conditions = append(conditions, "parent_id IS NULL")
+	}
+	if filter.Actionable {
+		conditions = append(conditions, `is_completed = false AND NOT EXISTS (
+			SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocker_id
+			WHERE d.task_id = tasks.id AND b.is_completed = false AND b.deleted_at IS NULL
+		)`)
+	}
+	if filter.Priority != "" {
+		addCondition("priority = $%d", filter.Priority)
+	}
+	if filter.ProjectID != nil {
+		if *filter.ProjectID == 0 {
+			conditions = append(conditions, "project_id IS NULL")
+		} else {
+			addCondition("project_id = $%d", *filter.ProjectID)
+		}
+	}
+	if filter.AssigneeID != 0 {
+		addCondition("assignee_id = $%d", filter.AssigneeID)
+	}
+	if filter.Unassigned {
+		conditions = append(conditions, "assignee_id IS NULL")
+	}
+	if filter.DueFrom != nil {
+		addCondition("due_date >= $%d", *filter.DueFrom)
+	}
+	if filter.DueTo != nil {
+		addCondition("due_date <= $%d", *filter.DueTo)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition("created_at <= $%d", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		addCondition("updated_at >= $%d", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		addCondition("updated_at <= $%d", *filter.UpdatedTo)
+	}
+	if len(filter.TagIDs) > 0 {
+		if filter.MatchAllTags {
+			args = append(args, len(filter.TagIDs))
+			addCondition(fmt.Sprintf(
+				"id IN (SELECT task_id FROM task_tags WHERE tag_id = ANY($%%d) GROUP BY task_id HAVING COUNT(*) = $%d)",
+				len(args),
+			), pq.Array(filter.TagIDs))
+		} else {
+			addCondition("id IN (SELECT task_id FROM task_tags WHERE tag_id = ANY($%d))", pq.Array(filter.TagIDs))
+		}
+	}
+
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM tasks WHERE ` + where
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	sortColumn, ok := taskSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = taskSortColumns["created_at"]
+	}
+	// The manual order reads top to bottom, so it defaults to ascending.
+	order := "DESC"
+	if strings.EqualFold(filter.Order, "asc") || (filter.Order == "" && filter.SortBy == "position") {
+		order = "ASC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM tasks
+		WHERE %s
+		ORDER BY %s %s NULLS LAST, id %s
+		LIMIT $%d OFFSET $%d
+	`, taskColumns, where, sortColumn, order, order, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []model.Task{}
+	for rows.Next() {
+		var task model.Task
+		err := scanTask(rows, &task)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+
+	return tasks, total, nil
+}
+
+// Update saves a task and records event for it, if any. Moving it under
+// another parent is rejected when that would create a cycle or exceed
+// MaxTaskDepth.
+func (r *TaskRepository) Update(task *model.Task, event *model.TaskEvent) error {
+	return r.Save(&TaskUpdate{Task: task, Event: event})
+}
+
+// TaskUpdate is a change to one task saved by Save. TagIDs replace the
+// tags of the task when SetTags is set, and CompleteSubtasks completes its
+// descendants on behalf of ActorID.
+type TaskUpdate struct {
+	Task                *model.Task
+	Event               *model.TaskEvent
+	TagIDs              []int
+	SetTags             bool
+	RescheduleReminders bool
+	CompleteSubtasks    bool
+	ActorID             int
+	Next                *NextOccurrence
+}
+
+// NextOccurrence is the task that continues a recurring series after
+// PreviousID was completed. It is created with Event and gets the tags and
+// offset reminders of the previous task.
+type NextOccurrence struct {
+	PreviousID int
+	Task       *model.Task
+	Event      *model.TaskEvent
+}
+
+// Save applies a TaskUpdate in one transaction, so the task, its tags,
+// reminders and subtasks and the next occurrence are changed together or
+// not at all. Tags and subtasks are limited to those of the task's owner.
+func (r *TaskRepository) Save(update *TaskUpdate) error {
+	task := update.Task
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The next occurrence locks the workspace to append to its order; take
+	// that lock before the task row, in the same order as Move.
+	if update.Next != nil {
+		if err := lockWorkspace(tx, task.WorkspaceID); err != nil {
+			return err
+		}
+	}
+
+	if task.ParentID.Valid {
+		if err := checkParent(tx, task.ID, int(task.ParentID.Int64), task.UserID); err != nil {
+			return err
+		}
+	}
+
+	if err := updateTaskRow(tx, task); err != nil {
+		return err
+	}
+
+	if update.Event != nil {
+		update.Event.TaskID = task.ID
+		if err := insertTaskEvent(tx, update.Event); err != nil {
+			return err
+		}
+	}
+
+	if update.SetTags {
+		if err := setTaskTags(tx, task.ID, update.TagIDs, task.UserID); err != nil {
+			return err
+		}
+	}
+
+	if update.RescheduleReminders {
+		if err := rescheduleReminders(tx, task.ID, task.DueDate); err != nil {
+			return err
+		}
+	}
+
+	if update.CompleteSubtasks {
+		if err := completeSubtasks(tx, task.ID, task.UserID, update.ActorID); err != nil {
+			return err
+		}
+	}
+
+	if update.Next != nil {
+		if err := insertNextOccurrence(tx, update.Next); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Move places a task directly before or after targetID in the manual order
+// of its workspace and returns its new position. Only the moved task is
+// written unless there is no room left between the neighbours, in which
+// case all positions of the workspace are rebalanced first.
+func (r *TaskRepository) Move(id, targetID int, after bool) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	workspaceID, err := taskWorkspace(tx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	// Serialize moves and inserts in one workspace so two of them cannot
+	// pick the same spot.
+	if err := lockWorkspace(tx, workspaceID); err != nil {
+		return 0, err
+	}
+
+	position, ok, err := positionNextTo(tx, id, workspaceID, targetID, after)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		if err := rebalancePositions(tx, workspaceID); err != nil {
+			return 0, err
+		}
+
+		position, ok, err = positionNextTo(tx, id, workspaceID, targetID, after)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, fmt.Errorf("failed to find a position for the task")
+		}
+	}
+
+	_, err = tx.Exec(
+		`UPDATE tasks SET position = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		position, id,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move task: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return position, nil
+}
+
+// positionNextTo returns the position halfway between targetID and its
+// neighbour on the requested side, ignoring the task being moved. It
+// reports false when the two are too close or targetID shares its position
+// with another task.
+func positionNextTo(tx *sql.Tx, id, workspaceID, targetID int, after bool) (int64, bool, error) {
+	var target int64
+	err := tx.QueryRow(
+		`SELECT position FROM tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL`,
+		targetID, workspaceID,
+	).Scan(&target)
 
 	if err == sql.ErrNoRows {
-		return fmt.Errorf("task not found or unauthorized")
+		return 0, false, fmt.Errorf("target task not found")
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to update task: %w", err)
+		return 0, false, fmt.Errorf("failed to get target task: %w", err)
+	}
+
+	var ties int
+	err = tx.QueryRow(
+		`SELECT COUNT(*) FROM tasks WHERE workspace_id = $1 AND position = $2 AND id <> $3 AND id <> $4 AND deleted_at IS NULL`,
+		workspaceID, target, id, targetID,
+	).Scan(&ties)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to check task positions: %w", err)
+	}
+
+	if ties > 0 {
+		return 0, false, nil
+	}
+
+	neighbourQuery := `SELECT MAX(position) FROM tasks WHERE workspace_id = $1 AND position < $2 AND id <> $3 AND deleted_at IS NULL`
+	if after {
+		neighbourQuery = `SELECT MIN(position) FROM tasks WHERE workspace_id = $1 AND position > $2 AND id <> $3 AND deleted_at IS NULL`
+	}
+
+	var neighbour sql.NullInt64
+	if err := tx.QueryRow(neighbourQuery, workspaceID, target, id).Scan(&neighbour); err != nil {
+		return 0, false, fmt.Errorf("failed to get neighbouring task: %w", err)
+	}
+
+	if !neighbour.Valid {
+		if after {
+			return target + TaskPositionGap, true, nil
+		}
+		return target - TaskPositionGap, true, nil
+	}
+
+	low, high := neighbour.Int64, target
+	if after {
+		low, high = target, neighbour.Int64
+	}
+
+	if high-low < 2 {
+		return 0, false, nil
+	}
+
+	return low + (high-low)/2, true, nil
+}
+
+// rebalancePositions spreads the positions of the workspace's tasks evenly,
+// TaskPositionGap apart, keeping their order.
+func rebalancePositions(tx *sql.Tx, workspaceID int) error {
+	query := `
+		UPDATE tasks
+		SET position = ranked.rank * $2
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) AS rank
+			FROM tasks
+			WHERE workspace_id = $1 AND deleted_at IS NULL
+		) ranked
+		WHERE tasks.id = ranked.id
+	`
+
+	if _, err := tx.Exec(query, workspaceID, TaskPositionGap); err != nil {
+		return fmt.Errorf("failed to rebalance task positions: %w", err)
 	}
 
 	return nil
 }
 
-func (r *TaskRepository) Delete(id int, userID int) error {
-	query := `DELETE FROM tasks WHERE id = $1 AND user_id = $2`
+// BulkTaskOperation is one change applied to many tasks by BulkApply.
+// Trash moves the tasks to the trash and AddTagID adds a tag to them;
+// otherwise Apply changes each locked task in place. Events are recorded
+// for Trash and AddTagID.
+type BulkTaskOperation struct {
+	TaskIDs  []int
+	Trash    bool
+	AddTagID int
+	Events   []*model.TaskEvent
+	Apply    func(task *model.Task) (*BulkTaskChange, error)
+}
+
+// BulkTaskChange is what Apply reports for a task it changed. The offset
+// reminders follow the new due date when Reschedule is set, and Next is
+// the next occurrence of a completed recurring task.
+type BulkTaskChange struct {
+	Event      *model.TaskEvent
+	Reschedule bool
+	Next       *NextOccurrence
+}
+
+// TaskItemError reports the task that made a bulk operation fail.
+type TaskItemError struct {
+	TaskID int
+	Err    error
+}
+
+func (e *TaskItemError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.TaskID, e.Err)
+}
+
+func (e *TaskItemError) Unwrap() error {
+	return e.Err
+}
 
-	result, err := r.db.Exec(query, id, userID)
+// BulkApply runs a bulk operation in one transaction. Every task is locked
+// and read first, so Apply sees its current state; if any of them is
+// missing, the tasks belong to different workspaces or Apply fails,
+// nothing is changed and a TaskItemError names the task. AddTagID is added
+// to tasks of other users as their tag of the same name, which they must
+// already have.
+func (r *TaskRepository) BulkApply(op *BulkTaskOperation) error {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	// Lock the workspace before the tasks, in the same order as Move, since
+	// next occurrences are appended to its order.
+	workspaceID, err := taskWorkspace(tx, op.TaskIDs[0])
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return &TaskItemError{TaskID: op.TaskIDs[0], Err: err}
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("task not found or unauthorized")
+	if err := lockWorkspace(tx, workspaceID); err != nil {
+		return err
+	}
+
+	tasks, err := lockTasks(tx, op.TaskIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.WorkspaceID != workspaceID {
+			return &TaskItemError{TaskID: task.ID, Err: fmt.Errorf("tasks of different workspaces cannot be changed together")}
+		}
+	}
+
+	switch {
+	case op.Trash:
+		query := `
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM tasks WHERE id = ANY($1)
+				UNION
+				SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+				WHERE t.deleted_at IS NULL
+			)
+			UPDATE tasks SET deleted_at = $2 WHERE id IN (SELECT id FROM subtree)
+		`
+		if _, err := tx.Exec(query, pq.Array(op.TaskIDs), time.Now().UTC()); err != nil {
+			return fmt.Errorf("failed to delete tasks: %w", err)
+		}
+	case op.AddTagID != 0:
+		if err := addTagToTasks(tx, tasks, op.AddTagID); err != nil {
+			return err
+		}
+	default:
+		for i := range tasks {
+			task := &tasks[i]
+			change, err := op.Apply(task)
+			if err != nil {
+				return &TaskItemError{TaskID: task.ID, Err: err}
+			}
+			if change == nil {
+				continue
+			}
+
+			if err := updateTaskRow(tx, task); err != nil {
+				return &TaskItemError{TaskID: task.ID, Err: err}
+			}
+
+			change.Event.TaskID = task.ID
+			if err := insertTaskEvent(tx, change.Event); err != nil {
+				return err
+			}
+
+			if change.Reschedule {
+				if err := rescheduleReminders(tx, task.ID, task.DueDate); err != nil {
+					return err
+				}
+			}
+
+			if change.Next != nil {
+				if err := insertNextOccurrence(tx, change.Next); err != nil {
+					return &TaskItemError{TaskID: task.ID, Err: err}
+				}
+			}
+		}
+	}
+
+	for _, event := range op.Events {
+		if err := insertTaskEvent(tx, event); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-func (r *TaskRepository) GetCompletedCount(userID int) (int, error) {
-	var count int
-	query := `SELECT COUNT(*) FROM tasks WHERE user_id = $1 AND is_completed = true`
+// lockTasks locks and returns the tasks of ids in their order. A
+// TaskItemError names the first one that is missing or in the trash.
+func lockTasks(tx *sql.Tx, ids []int) ([]model.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id = ANY($1) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`
 
-	err := r.db.QueryRow(query, userID).Scan(&count)
+	rows, err := tx.Query(query, pq.Array(ids))
 	if err != nil {
-		return 0, fmt.Errorf("failed to get completed count: %w", err)
+		return nil, fmt.Errorf("failed to lock tasks: %w", err)
 	}
+	defer rows.Close()
 
-	return count, nil
-}
\ No newline at end of file
+	byID := make(map[int]model.Task, len(ids))
+	for rows.Next() {
+		var task model.Task
+		if err := scanTask(rows, &task); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		byID[task.ID] = task
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock tasks: %w", err)
+	}
+
+	tasks := make([]model.Task, 0, len(ids))
+	for _, id := range ids {
+		task, ok := byID[id]
+		if !ok {
+			return nil, &TaskItemError{TaskID: id, Err: fmt.Errorf("task not found")}
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// addTagToTasks adds tagID to the tasks, using for each task the tag of its
+// owner with the same name. A TaskItemError names the first task whose
+// owner has no such tag.
+func addTagToTasks(tx *sql.Tx, tasks []model.Task, tagID int) error {
+	ownerIDs := make([]int, len(tasks))
+	for i, task := range tasks {
+		ownerIDs[i] = task.UserID
+	}
+
+	query := `
+		SELECT owned.user_id, owned.id
+		FROM tags tag
+		JOIN tags owned ON owned.name = tag.name AND owned.user_id = ANY($2)
+		WHERE tag.id = $1
+	`
+
+	rows, err := tx.Query(query, tagID, pq.Array(ownerIDs))
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tagIDs := make(map[int]int)
+	for rows.Next() {
+		var userID, id int
+		if err := rows.Scan(&userID, &id); err != nil {
+			return fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tagIDs[userID] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	for _, task := range tasks {
+		ownedID, ok := tagIDs[task.UserID]
+		if !ok {
+			return &TaskItemError{TaskID: task.ID, Err: fmt.Errorf("the task's owner has no tag with this name")}
+		}
+
+		_, err := tx.Exec(`INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, task.ID, ownedID)
+		if err != nil {
+			return fmt.Errorf("failed to tag tasks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetSubtasks returns the direct subtasks of a task.
+func (r *TaskRepository) GetSubtasks(parentID int, userID int) ([]model.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(query, parentID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []model.Task{}
+	for rows.Next() {
+		var task model.Task
+		if err := scanTask(rows, &task); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// GetSubtaskProgress counts the done and total direct subtasks of each of
+// the given tasks. Tasks without subtasks are missing from the result.
+func (r *TaskRepository) GetSubtaskProgress(taskIDs []int) (map[int]model.SubtaskProgress, error) {
+	progress := make(map[int]model.SubtaskProgress)
+	if len(taskIDs) == 0 {
+		return progress, nil
+	}
+
+	query := `
+		SELECT parent_id, COUNT(*) FILTER (WHERE is_completed), COUNT(*)
+		FROM tasks
+		WHERE parent_id = ANY($1) AND deleted_at IS NULL
+		GROUP BY parent_id
+	`
+
+	rows, err := r.db.Query(query, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtask progress: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID int
+		var p model.SubtaskProgress
+		if err := rows.Scan(&parentID, &p.Done, &p.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan subtask progress: %w", err)
+		}
+		progress[parentID] = p
+	}
+
+	return progress, nil
+}
+
+// completeSubtasks marks every descendant of a task as completed, moving it
+// to the user's first done status, and records a completed event by actorID
+// for each of them.
+func completeSubtasks(tx *sql.Tx, id int, userID int, actorID int) error {
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT id, 1 AS depth FROM tasks WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, d.depth + 1 FROM tasks t JOIN descendants d ON t.parent_id = d.id
+			WHERE d.depth < $3 AND t.deleted_at IS NULL
+		)
+		, open AS (
+			SELECT id, status_id FROM tasks WHERE id IN (SELECT id FROM descendants) AND is_completed = false
+		)
+		, completed AS (
+			UPDATE tasks
+			SET is_completed = true, status_id = done.id, updated_at = CURRENT_TIMESTAMP
+			FROM open, (
+				SELECT id FROM task_statuses WHERE user_id = $2 AND is_done ORDER BY position, id LIMIT 1
+			) done
+			WHERE tasks.id = open.id
+			RETURNING tasks.id, open.status_id AS old_status_id, done.id AS status_id
+		)
+		INSERT INTO task_events (task_id, actor_id, action, changes)
+		SELECT id, $4, $5, jsonb_build_object(
+			'is_completed', jsonb_build_object('from', false, 'to', true),
+			'status_id', jsonb_build_object('from', old_status_id, 'to', status_id)
+		)
+		FROM completed
+	`
+
+	if _, err := tx.Exec(query, id, userID, MaxTaskDepth, actorID, model.TaskEventCompleted); err != nil {
+		return fmt.Errorf("failed to complete subtasks: %w", err)
+	}
+
+	return nil
+}
+
+// Delete moves a task and its subtasks to the trash and records event for
+// the task. They share one deleted_at so Restore can bring back exactly
+// this batch.
+func (r *TaskRepository) Delete(id int, userID int, event *model.TaskEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at IS NULL
+		)
+		UPDATE tasks SET deleted_at = $3 WHERE id IN (SELECT id FROM subtree)
+	`
+
+	result, err := tx.Exec(query, id, userID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found or unauthorized")
+	}
+
+	if event != nil {
+		event.TaskID = id
+		if err := insertTaskEvent(tx, event); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrash returns a page of the trashed tasks of a workspace, newest
+// first, and the total number of them. Subtasks trashed together with their parent are left out.
+func (r *TaskRepository) GetTrash(workspaceID int, limit, offset int) ([]model.Task, int, error) {
+	where := `
+		t.workspace_id = $1 AND t.deleted_at IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM tasks p WHERE p.id = t.parent_id AND p.deleted_at = t.deleted_at)
+	`
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM tasks t WHERE `+where, workspaceID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count trashed tasks: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM tasks t
+		WHERE %s
+		ORDER BY t.deleted_at DESC, t.id DESC
+		LIMIT $2 OFFSET $3
+	`, taskColumns, where)
+
+	rows, err := r.db.Query(query, workspaceID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get trashed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []model.Task{}
+	for rows.Next() {
+		var task model.Task
+		if err := scanTask(rows, &task); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, total, nil
+}
+
+// GetTrashedByID returns a task from the trash.
+func (r *TaskRepository) GetTrashedByID(id int, userID int) (*model.Task, error) {
+	task := &model.Task{}
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+	`
+
+	err := scanTask(r.db.QueryRow(query, id, userID), task)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("task not found in trash")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return task, nil
+}
+
+// Restore takes a task out of the trash together with the subtasks that
+// were trashed with it and records event for the task. A task whose parent
+// is still in the trash becomes a top-level task.
+func (r *TaskRepository) Restore(id int, userID int, event *model.TaskEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	err = tx.QueryRow(
+		`SELECT deleted_at FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL FOR UPDATE`,
+		id, userID,
+	).Scan(&deletedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("task not found in trash")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE tasks SET parent_id = NULL
+		WHERE id = $1 AND parent_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL)
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to detach task: %w", err)
+	}
+
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = $1
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at = $2
+		)
+		UPDATE tasks SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT id FROM subtree)
+	`
+
+	if _, err := tx.Exec(query, id, deletedAt); err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	if event != nil {
+		event.TaskID = id
+		if err := insertTaskEvent(tx, event); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Purge permanently deletes a trashed task and its subtasks.
+func (r *TaskRepository) Purge(id int, userID int) error {
+	result, err := r.db.Exec(
+		`DELETE FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`,
+		id, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to purge task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found in trash")
+	}
+
+	return nil
+}
+
+// PurgeTrash empties the trash of a workspace and returns the number of
+// tasks deleted. A non-zero userID limits it to the tasks of that user.
+func (r *TaskRepository) PurgeTrash(workspaceID, userID int) (int, error) {
+	result, err := r.db.Exec(
+		`DELETE FROM tasks WHERE workspace_id = $1 AND ($2 = 0 OR user_id = $2) AND deleted_at IS NOT NULL`,
+		workspaceID, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// PurgeDeletedBefore permanently deletes every task trashed before cutoff.
+func (r *TaskRepository) PurgeDeletedBefore(cutoff time.Time) (int, error) {
+	result, err := r.db.Exec(`DELETE FROM tasks WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *TaskRepository) GetCompletedCount(userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM tasks WHERE user_id = $1 AND is_completed = true AND deleted_at IS NULL`
+
+	err := r.db.QueryRow(query, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get completed count: %w", err)
+	}
+
+	return count, nil
+}
+
+// insertTask inserts a task at the end of its workspace's manual order and
+// records event for it, if any.
+func insertTask(tx *sql.Tx, task *model.Task, event *model.TaskEvent) error {
+	if task.ParentID.Valid {
+		if err := checkParent(tx, 0, int(task.ParentID.Int64), task.UserID); err != nil {
+			return err
+		}
+	}
+
+	if err := lockWorkspace(tx, task.WorkspaceID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tasks (user_id, workspace_id, parent_id, project_id, assignee_id, title, description, is_completed, status_id,
+			priority, due_date, recurrence_rule, recurrence_timezone, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+			COALESCE((SELECT MAX(position) FROM tasks WHERE workspace_id = $2), 0) + $14)
+		RETURNING id, position, created_at, updated_at
+	`
+
+	err := tx.QueryRow(
+		query,
+		task.UserID,
+		task.WorkspaceID,
+		task.ParentID,
+		task.ProjectID,
+		task.AssigneeID,
+		task.Title,
+		task.Description,
+		task.IsCompleted,
+		task.StatusID,
+		task.Priority,
+		task.DueDate,
+		task.RecurrenceRule,
+		task.RecurrenceTimezone,
+		TaskPositionGap,
+	).Scan(&task.ID, &task.Position, &task.CreatedAt, &task.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	if event != nil {
+		event.TaskID = task.ID
+		if err := insertTaskEvent(tx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertNextOccurrence creates the next task of a recurring series.
+func insertNextOccurrence(tx *sql.Tx, next *NextOccurrence) error {
+	if err := insertTask(tx, next.Task, next.Event); err != nil {
+		return fmt.Errorf("failed to create next occurrence: %w", err)
+	}
+
+	if err := copyReminderOffsets(tx, next.PreviousID, next.Task.ID, next.Task.DueDate); err != nil {
+		return err
+	}
+
+	return copyTaskTags(tx, next.PreviousID, next.Task.ID)
+}
+
+// updateTaskRow saves the fields of a task that is not in the trash.
+func updateTaskRow(tx *sql.Tx, task *model.Task) error {
+	query := `
+		UPDATE tasks
+		SET parent_id = $1, project_id = $2, assignee_id = $3, title = $4, description = $5, is_completed = $6, status_id = $7,
+			priority = $8, due_date = $9, recurrence_rule = $10, recurrence_timezone = $11, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $12 AND user_id = $13 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+
+	err := tx.QueryRow(
+		query,
+		task.ParentID,
+		task.ProjectID,
+		task.AssigneeID,
+		task.Title,
+		task.Description,
+		task.IsCompleted,
+		task.StatusID,
+		task.Priority,
+		task.DueDate,
+		task.RecurrenceRule,
+		task.RecurrenceTimezone,
+		task.ID,
+		task.UserID,
+	).Scan(&task.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("task not found or unauthorized")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	return nil
+}
+
+// checkParent verifies that parentID can become the parent of taskID (zero
+// for a new task): it must belong to userID, must not be taskID or one of
+// its descendants, and the moved subtree must fit within MaxTaskDepth.
+func checkParent(tx *sql.Tx, taskID, parentID, userID int) error {
+	if parentID == taskID {
+		return fmt.Errorf("a task cannot be its own parent")
+	}
+
+	ancestorsQuery := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 1 AS depth FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, t.parent_id, a.depth + 1 FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+			WHERE a.depth <= $3
+		)
+		SELECT id, depth FROM ancestors
+	`
+
+	rows, err := tx.Query(ancestorsQuery, parentID, userID, MaxTaskDepth)
+	if err != nil {
+		return fmt.Errorf("failed to check parent task: %w", err)
+	}
+	defer rows.Close()
+
+	parentDepth := 0
+	for rows.Next() {
+		var id, depth int
+		if err := rows.Scan(&id, &depth); err != nil {
+			return fmt.Errorf("failed to scan parent task: %w", err)
+		}
+		if id == taskID {
+			return fmt.Errorf("a task cannot be moved under one of its own subtasks")
+		}
+		if depth > parentDepth {
+			parentDepth = depth
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check parent task: %w", err)
+	}
+
+	if parentDepth == 0 {
+		return fmt.Errorf("parent task not found")
+	}
+
+	height := 1
+	if taskID != 0 {
+		heightQuery := `
+			WITH RECURSIVE subtree AS (
+				SELECT id, 1 AS level FROM tasks WHERE id = $1
+				UNION ALL
+				SELECT t.id, s.level + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id
+				WHERE s.level <= $2 AND t.deleted_at IS NULL
+			)
+			SELECT MAX(level) FROM subtree
+		`
+		if err := tx.QueryRow(heightQuery, taskID, MaxTaskDepth).Scan(&height); err != nil {
+			return fmt.Errorf("failed to check task depth: %w", err)
+		}
+	}
+
+	if parentDepth+height > MaxTaskDepth {
+		return fmt.Errorf("subtasks cannot be nested more than %d levels deep", MaxTaskDepth)
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/lib/pq"
+)
+
+type TaskStatusRepository struct {
+	db *sql.DB
+}
+
+func NewTaskStatusRepository(db *sql.DB) *TaskStatusRepository {
+	return &TaskStatusRepository{db: db}
+}
+
+const taskStatusColumns = `id, user_id, name, position, is_done, created_at, updated_at`
+
+func scanTaskStatus(row rowScanner, status *model.TaskStatus) error {
+	return row.Scan(
+		&status.ID,
+		&status.UserID,
+		&status.Name,
+		&status.Position,
+		&status.IsDone,
+		&status.CreatedAt,
+		&status.UpdatedAt,
+	)
+}
+
+// EnsureDefaults gives a user the default statuses unless they already
+// have some.
+func (r *TaskStatusRepository) EnsureDefaults(userID int) error {
+	names := make([]string, len(model.DefaultTaskStatuses))
+	positions := make([]int64, len(model.DefaultTaskStatuses))
+	done := make([]bool, len(model.DefaultTaskStatuses))
+	for i, status := range model.DefaultTaskStatuses {
+		names[i] = status.Name
+		positions[i] = int64(status.Position)
+		done[i] = status.IsDone
+	}
+
+	query := `
+		INSERT INTO task_statuses (user_id, name, position, is_done)
+		SELECT $1, d.name, d.position, d.is_done
+		FROM UNNEST($2::text[], $3::int[], $4::bool[]) AS d(name, position, is_done)
+		WHERE NOT EXISTS (SELECT 1 FROM task_statuses WHERE user_id = $1)
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := r.db.Exec(query, userID, pq.Array(names), pq.Array(positions), pq.Array(done)); err != nil {
+		return fmt.Errorf("failed to create default statuses: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaskStatusRepository) Create(status *model.TaskStatus) error {
+	query := `
+		INSERT INTO task_statuses (user_id, name, position, is_done)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(query, status.UserID, status.Name, status.Position, status.IsDone).Scan(
+		&status.ID,
+		&status.CreatedAt,
+		&status.UpdatedAt,
+	)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("status already exists")
+		}
+		return fmt.Errorf("failed to create status: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaskStatusRepository) GetByID(id int, userID int) (*model.TaskStatus, error) {
+	status := &model.TaskStatus{}
+	query := `SELECT ` + taskStatusColumns + ` FROM task_statuses WHERE id = $1 AND user_id = $2`
+
+	err := scanTaskStatus(r.db.QueryRow(query, id, userID), status)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("status not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	return status, nil
+}
+
+// GetAllByUserID returns the statuses of a user in board order.
+func (r *TaskStatusRepository) GetAllByUserID(userID int) ([]model.TaskStatus, error) {
+	query := `SELECT ` + taskStatusColumns + ` FROM task_statuses WHERE user_id = $1 ORDER BY position, id`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses: %w", err)
+	}
+	defer rows.Close()
+
+	statuses := []model.TaskStatus{}
+	for rows.Next() {
+		var status model.TaskStatus
+		if err := scanTaskStatus(rows, &status); err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// GetByIDs returns the statuses with the given IDs keyed by ID.
+func (r *TaskStatusRepository) GetByIDs(ids []int) (map[int]model.TaskStatus, error) {
+	statuses := make(map[int]model.TaskStatus)
+	if len(ids) == 0 {
+		return statuses, nil
+	}
+
+	query := `SELECT ` + taskStatusColumns + ` FROM task_statuses WHERE id = ANY($1)`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status model.TaskStatus
+		if err := scanTaskStatus(rows, &status); err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		statuses[status.ID] = status
+	}
+
+	return statuses, nil
+}
+
+// Update saves a status. When its done flag changes, is_completed of the
+// tasks in it changes along with it.
+func (r *TaskStatusRepository) Update(status *model.TaskStatus) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE task_statuses
+		SET name = $1, position = $2, is_done = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND user_id = $5
+		RETURNING updated_at
+	`
+
+	err = tx.QueryRow(query, status.Name, status.Position, status.IsDone, status.ID, status.UserID).Scan(&status.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("status not found")
+	}
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("status already exists")
+		}
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`UPDATE tasks SET is_completed = $1, updated_at = CURRENT_TIMESTAMP WHERE status_id = $2 AND is_completed <> $1`,
+		status.IsDone, status.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update tasks of status: %w", err)
+	}
+
+	if err := checkStatusKinds(tx, status.UserID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a status. Its tasks, including those in the trash, are
+// moved to moveTo first; without one the status must not have any tasks.
+func (r *TaskStatusRepository) Delete(id int, userID int, moveTo int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if moveTo != 0 {
+		query := `
+			UPDATE tasks
+			SET status_id = s.id, is_completed = s.is_done, updated_at = CURRENT_TIMESTAMP
+			FROM task_statuses s
+			WHERE s.id = $1 AND s.user_id = $3 AND tasks.status_id = $2
+		`
+
+		if _, err := tx.Exec(query, moveTo, id, userID); err != nil {
+			return fmt.Errorf("failed to move tasks of status: %w", err)
+		}
+	}
+
+	var inUse bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM tasks WHERE status_id = $1)`, id).Scan(&inUse)
+	if err != nil {
+		return fmt.Errorf("failed to check tasks of status: %w", err)
+	}
+
+	if inUse {
+		return fmt.Errorf("status still has tasks, choose a status to move them to")
+	}
+
+	result, err := tx.Exec(`DELETE FROM task_statuses WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("status not found")
+	}
+
+	if err := checkStatusKinds(tx, userID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// checkStatusKinds makes sure a user keeps at least one open and one done
+// status, so is_completed can always be mapped to a status.
+func checkStatusKinds(tx *sql.Tx, userID int) error {
+	var open, done int
+	err := tx.QueryRow(
+		`SELECT COUNT(*) FILTER (WHERE NOT is_done), COUNT(*) FILTER (WHERE is_done) FROM task_statuses WHERE user_id = $1`,
+		userID,
+	).Scan(&open, &done)
+	if err != nil {
+		return fmt.Errorf("failed to check statuses: %w", err)
+	}
+
+	if open == 0 || done == 0 {
+		return fmt.Errorf("at least one open and one done status are required")
+	}
+
+	return nil
+}
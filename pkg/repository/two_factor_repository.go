@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// GetSecret returns the stored TOTP secret of a user and whether it has
+// been confirmed. An empty secret means two-factor auth was never set up.
+func (r *TwoFactorRepository) GetSecret(userID int) (string, bool, error) {
+	var secret sql.NullString
+	var enabledAt sql.NullTime
+	query := `SELECT totp_secret, totp_enabled_at FROM users WHERE id = $1`
+
+	err := r.db.QueryRow(query, userID).Scan(&secret, &enabledAt)
+
+	if err == sql.ErrNoRows {
+		return "", false, fmt.Errorf("user not found")
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get totp secret: %w", err)
+	}
+
+	return secret.String, enabledAt.Valid, nil
+}
+
+// SetPendingSecret stores a new, not yet confirmed secret. It fails when
+// two-factor auth is already enabled.
+func (r *TwoFactorRepository) SetPendingSecret(userID int, secret string) error {
+	query := `
+		UPDATE users
+		SET totp_secret = $1, totp_last_step = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND totp_enabled_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, secret, userID)
+	if err != nil {
+		return fmt.Errorf("failed to store totp secret: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	return nil
+}
+
+// Enable confirms the pending secret and replaces the recovery codes.
+func (r *TwoFactorRepository) Enable(userID int, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`UPDATE users SET totp_enabled_at = CURRENT_TIMESTAMP, totp_last_step = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		step, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	if err := replaceRecoveryCodes(tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) Disable(userID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM totp_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userID int, recoveryCodeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MarkStepUsed records the time step of an accepted code. It returns false
+// when that step, or a later one, was already used, so a code cannot be
+// replayed.
+func (r *TwoFactorRepository) MarkStepUsed(userID int, step int64) (bool, error) {
+	query := `
+		UPDATE users
+		SET totp_last_step = $1
+		WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1)
+	`
+
+	result, err := r.db.Exec(query, step, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to record totp usage: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ConsumeRecoveryCode marks an unused recovery code as used. It returns
+// false when no such code exists.
+func (r *TwoFactorRepository) ConsumeRecoveryCode(userID int, codeHash string) (bool, error) {
+	query := `
+		UPDATE totp_recovery_codes
+		SET used_at = $1
+		WHERE id = (
+			SELECT id FROM totp_recovery_codes
+			WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+			LIMIT 1
+		)
+	`
+
+	result, err := r.db.Exec(query, time.Now().UTC(), userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM totp_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	if err := r.db.QueryRow(query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, userID int, recoveryCodeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM totp_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		_, err := tx.Exec(
+			`INSERT INTO totp_recovery_codes (user_id, code_hash) VALUES ($1, $2)`,
+			userID, codeHash,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store recovery code: %w", err)
+		}
+	}
+
+	return nil
+}
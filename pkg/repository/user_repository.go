@@ -39,7 +39,7 @@ func (r *UserRepository) Create(user *model.User) error {
 func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
 	user := &model.User{}
 	query := `
-		SELECT id, email, password_hash, name, created_at, updated_at
+		SELECT id, email, password_hash, name, email_verified_at, totp_enabled_at, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -49,6 +49,8 @@ func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
 		&user.Email,
 		&user.PasswordHash,
 		&user.Name,
+		&user.EmailVerifiedAt,
+		&user.TOTPEnabledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -67,7 +69,7 @@ func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
 func (r *UserRepository) GetByID(id int) (*model.User, error) {
 	user := &model.User{}
 	query := `
-		SELECT id, email, password_hash, name, created_at, updated_at
+		SELECT id, email, password_hash, name, email_verified_at, totp_enabled_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -77,6 +79,8 @@ func (r *UserRepository) GetByID(id int) (*model.User, error) {
 		&user.Email,
 		&user.PasswordHash,
 		&user.Name,
+		&user.EmailVerifiedAt,
+		&user.TOTPEnabledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -102,4 +106,142 @@ func (r *UserRepository) EmailExists(email string) (bool, error) {
 	}
 
 	return exists, nil
+}
+
+func (r *UserRepository) UpdatePassword(id int, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	result, err := r.db.Exec(query, passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// MarkEmailVerified flags the address as verified, provided the user still
+// uses the address the verification link was sent to.
+func (r *UserRepository) MarkEmailVerified(id int, email string) error {
+	query := `
+		UPDATE users
+		SET email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND email = $2
+	`
+
+	result, err := r.db.Exec(query, id, email)
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("email address has changed, request a new verification link")
+	}
+
+	return nil
+}
+
+// Update saves the email, name and verification state of a user. A
+// non-empty passwordHash replaces the password in the same transaction and
+// revokes all refresh tokens, so a failed write never leaves only half of
+// the profile change behind.
+func (r *UserRepository) Update(user *model.User, passwordHash string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET email = $1, name = $2, email_verified_at = $3,
+			password_hash = COALESCE(NULLIF($4, ''), password_hash),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+		RETURNING updated_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		user.Email,
+		user.Name,
+		user.EmailVerifiedAt,
+		passwordHash,
+		user.ID,
+	).Scan(&user.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("user not found")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if passwordHash != "" {
+		_, err := tx.Exec(
+			`UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`,
+			user.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a user with everything they own. Workspaces they share
+// with others keep their tasks and projects, see leaveWorkspaces.
+func (r *UserRepository) Delete(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := leaveWorkspaces(tx, id); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
\ No newline at end of file
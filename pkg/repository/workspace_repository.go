@@ -0,0 +1,633 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+)
+
+type WorkspaceRepository struct {
+	db *sql.DB
+}
+
+func NewWorkspaceRepository(db *sql.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+// defaultMembershipQuery selects the workspace a user joined first.
+const defaultMembershipQuery = `
+	SELECT workspace_id, user_id, role, created_at
+	FROM workspace_members
+	WHERE user_id = $1
+	ORDER BY created_at, workspace_id
+	LIMIT 1
+`
+
+func scanMembership(row rowScanner, member *model.WorkspaceMember) error {
+	return row.Scan(&member.WorkspaceID, &member.UserID, &member.Role, &member.CreatedAt)
+}
+
+// Create inserts a workspace with ownerID as its owner.
+func (r *WorkspaceRepository) Create(workspace *model.Workspace, ownerID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := createWorkspace(tx, workspace, ownerID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lockWorkspace locks a workspace row until the transaction ends, to
+// serialize changes to the task order and dependencies of the workspace.
+// NO KEY UPDATE leaves rows that reference the workspace insertable.
+func lockWorkspace(tx *sql.Tx, workspaceID int) error {
+	if _, err := tx.Exec(`SELECT id FROM workspaces WHERE id = $1 FOR NO KEY UPDATE`, workspaceID); err != nil {
+		return fmt.Errorf("failed to lock workspace: %w", err)
+	}
+
+	return nil
+}
+
+func createWorkspace(tx *sql.Tx, workspace *model.Workspace, ownerID int) error {
+	query := `
+		INSERT INTO workspaces (name, created_by)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := tx.QueryRow(query, workspace.Name, ownerID).Scan(&workspace.ID, &workspace.CreatedAt, &workspace.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)`,
+		workspace.ID, ownerID, model.WorkspaceRoleOwner,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add workspace owner: %w", err)
+	}
+
+	workspace.CreatedBy = sql.NullInt64{Int64: int64(ownerID), Valid: true}
+	workspace.Role = model.WorkspaceRoleOwner
+
+	return nil
+}
+
+// GetDefaultMembership returns the membership of the workspace a user
+// joined first. Users without any workspace get a new one called
+// personalName.
+func (r *WorkspaceRepository) GetDefaultMembership(userID int, personalName string) (*model.WorkspaceMember, error) {
+	member := &model.WorkspaceMember{}
+	err := scanMembership(r.db.QueryRow(defaultMembershipQuery, userID), member)
+	if err == nil {
+		return member, nil
+	}
+
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get workspace membership: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialize the first requests of a user so only one of them creates
+	// the workspace.
+	if _, err := tx.Exec(`SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	err = scanMembership(tx.QueryRow(defaultMembershipQuery, userID), member)
+	if err == sql.ErrNoRows {
+		workspace := &model.Workspace{Name: personalName}
+		if err := createWorkspace(tx, workspace, userID); err != nil {
+			return nil, err
+		}
+		err = scanMembership(tx.QueryRow(defaultMembershipQuery, userID), member)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace membership: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return member, nil
+}
+
+func (r *WorkspaceRepository) GetMembership(workspaceID, userID int) (*model.WorkspaceMember, error) {
+	member := &model.WorkspaceMember{}
+	query := `
+		SELECT workspace_id, user_id, role, created_at
+		FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2
+	`
+
+	err := scanMembership(r.db.QueryRow(query, workspaceID, userID), member)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("workspace not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace membership: %w", err)
+	}
+
+	return member, nil
+}
+
+// GetByID returns a workspace together with the role userID has in it.
+func (r *WorkspaceRepository) GetByID(id int, userID int) (*model.Workspace, error) {
+	workspace := &model.Workspace{}
+	query := `
+		SELECT w.id, w.name, w.created_by, m.role, w.created_at, w.updated_at
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE w.id = $1 AND m.user_id = $2
+	`
+
+	err := r.db.QueryRow(query, id, userID).Scan(
+		&workspace.ID,
+		&workspace.Name,
+		&workspace.CreatedBy,
+		&workspace.Role,
+		&workspace.CreatedAt,
+		&workspace.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("workspace not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	return workspace, nil
+}
+
+// GetAllByUserID returns the workspaces a user is a member of, in the
+// order they joined them.
+func (r *WorkspaceRepository) GetAllByUserID(userID int) ([]model.Workspace, error) {
+	query := `
+		SELECT w.id, w.name, w.created_by, m.role, w.created_at, w.updated_at
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1
+		ORDER BY m.created_at, w.id
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	workspaces := []model.Workspace{}
+	for rows.Next() {
+		var workspace model.Workspace
+		err := rows.Scan(
+			&workspace.ID,
+			&workspace.Name,
+			&workspace.CreatedBy,
+			&workspace.Role,
+			&workspace.CreatedAt,
+			&workspace.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) Update(workspace *model.Workspace) error {
+	query := `
+		UPDATE workspaces
+		SET name = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(query, workspace.Name, workspace.ID).Scan(&workspace.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("workspace not found")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a workspace together with its tasks and projects.
+func (r *WorkspaceRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM workspaces WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("workspace not found")
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) GetMembers(workspaceID int) ([]model.WorkspaceMember, error) {
+	query := `
+		SELECT m.workspace_id, m.user_id, u.email, u.name, m.role, m.created_at
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY m.created_at, m.user_id
+	`
+
+	rows, err := r.db.Query(query, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []model.WorkspaceMember{}
+	for rows.Next() {
+		var member model.WorkspaceMember
+		err := rows.Scan(&member.WorkspaceID, &member.UserID, &member.Email, &member.Name, &member.Role, &member.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole changes the role of a member. The owner keeps their
+// role.
+func (r *WorkspaceRepository) UpdateMemberRole(workspaceID, userID int, role model.WorkspaceRole) error {
+	result, err := r.db.Exec(
+		`UPDATE workspace_members SET role = $1 WHERE workspace_id = $2 AND user_id = $3 AND role <> 'owner'`,
+		role, workspaceID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update workspace member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("member not found")
+	}
+
+	return nil
+}
+
+// RemoveMember removes a member other than the owner from a workspace.
+func (r *WorkspaceRepository) RemoveMember(workspaceID, userID int) error {
+	result, err := r.db.Exec(
+		`DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2 AND role <> 'owner'`,
+		workspaceID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("member not found")
+	}
+
+	return nil
+}
+
+// CreateInvitation stores a new invitation and withdraws any earlier
+// pending invitation of the same address to the workspace.
+func (r *WorkspaceRepository) CreateInvitation(invitation *model.WorkspaceInvitation) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`DELETE FROM workspace_invitations WHERE workspace_id = $1 AND LOWER(email) = LOWER($2) AND accepted_at IS NULL`,
+		invitation.WorkspaceID, invitation.Email,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to withdraw invitations: %w", err)
+	}
+
+	query := `
+		INSERT INTO workspace_invitations (workspace_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		invitation.WorkspaceID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+	).Scan(&invitation.ID, &invitation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingInvitations returns the invitations to a workspace that were
+// neither accepted nor expired.
+func (r *WorkspaceRepository) GetPendingInvitations(workspaceID int) ([]model.WorkspaceInvitation, error) {
+	query := `
+		SELECT id, workspace_id, email, role, invited_by, expires_at, created_at
+		FROM workspace_invitations
+		WHERE workspace_id = $1 AND accepted_at IS NULL AND expires_at > $2
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, workspaceID, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []model.WorkspaceInvitation{}
+	for rows.Next() {
+		var invitation model.WorkspaceInvitation
+		err := rows.Scan(
+			&invitation.ID,
+			&invitation.WorkspaceID,
+			&invitation.Email,
+			&invitation.Role,
+			&invitation.InvitedBy,
+			&invitation.ExpiresAt,
+			&invitation.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, nil
+}
+
+// DeleteInvitation withdraws a pending invitation.
+func (r *WorkspaceRepository) DeleteInvitation(id, workspaceID int) error {
+	result, err := r.db.Exec(
+		`DELETE FROM workspace_invitations WHERE id = $1 AND workspace_id = $2 AND accepted_at IS NULL`,
+		id, workspaceID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("invitation not found")
+	}
+
+	return nil
+}
+
+// AcceptInvitation consumes a pending, unexpired invitation sent to email
+// and adds userID to its workspace. Users who are already members keep
+// their role. It returns the ID of the workspace.
+func (r *WorkspaceRepository) AcceptInvitation(tokenHash string, userID int, email string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE workspace_invitations
+		SET accepted_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND accepted_at IS NULL AND expires_at > $2 AND LOWER(email) = LOWER($3)
+		RETURNING workspace_id, role
+	`
+
+	var workspaceID int
+	var role model.WorkspaceRole
+	err = tx.QueryRow(query, tokenHash, time.Now().UTC(), email).Scan(&workspaceID, &role)
+
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("invalid or expired invitation")
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		workspaceID, userID, role,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add workspace member: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return workspaceID, nil
+}
+
+// leaveWorkspaces prepares the deletion of a user. Workspaces without other
+// members are deleted. In the others the user's ownership passes to the
+// earliest admin, or else the earliest member, and the user's tasks and
+// projects are handed to the workspace owner so they outlive the account.
+func leaveWorkspaces(tx *sql.Tx, userID int) error {
+	_, err := tx.Exec(`
+		DELETE FROM workspaces w
+		WHERE EXISTS (SELECT 1 FROM workspace_members WHERE workspace_id = w.id AND user_id = $1)
+			AND NOT EXISTS (SELECT 1 FROM workspace_members WHERE workspace_id = w.id AND user_id <> $1)
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete workspaces: %w", err)
+	}
+
+	rows, err := tx.Query(
+		`SELECT workspace_id, role FROM workspace_members WHERE user_id = $1 ORDER BY workspace_id`,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get workspaces: %w", err)
+	}
+
+	roles := make(map[int]model.WorkspaceRole)
+	var workspaceIDs []int
+	for rows.Next() {
+		var workspaceID int
+		var role model.WorkspaceRole
+		if err := rows.Scan(&workspaceID, &role); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		roles[workspaceID] = role
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate workspaces: %w", err)
+	}
+
+	for _, workspaceID := range workspaceIDs {
+		if roles[workspaceID] == model.WorkspaceRoleOwner {
+			if err := passOwnership(tx, workspaceID, userID); err != nil {
+				return err
+			}
+		}
+
+		var ownerID int
+		err := tx.QueryRow(
+			`SELECT user_id FROM workspace_members WHERE workspace_id = $1 AND role = $2`,
+			workspaceID, model.WorkspaceRoleOwner,
+		).Scan(&ownerID)
+		if err != nil {
+			return fmt.Errorf("failed to get workspace owner: %w", err)
+		}
+
+		if err := handOverItems(tx, workspaceID, userID, ownerID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// passOwnership makes the earliest admin, or else the earliest member, the
+// owner of a workspace instead of userID.
+func passOwnership(tx *sql.Tx, workspaceID, userID int) error {
+	_, err := tx.Exec(
+		`UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID, userID, model.WorkspaceRoleMember,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to pass workspace ownership: %w", err)
+	}
+
+	query := `
+		UPDATE workspace_members SET role = $3
+		WHERE workspace_id = $1 AND user_id = (
+			SELECT user_id FROM workspace_members
+			WHERE workspace_id = $1 AND user_id <> $2
+			ORDER BY role = $4 DESC, created_at, user_id
+			LIMIT 1
+		)
+	`
+
+	if _, err := tx.Exec(query, workspaceID, userID, model.WorkspaceRoleOwner, model.WorkspaceRoleAdmin); err != nil {
+		return fmt.Errorf("failed to pass workspace ownership: %w", err)
+	}
+
+	return nil
+}
+
+// handOverItems gives the tasks and projects of fromID in a workspace to
+// toID. Tasks move to the status of toID with the same name and kind, or
+// else the first one of the same kind, and keep their tags as tags of toID.
+func handOverItems(tx *sql.Tx, workspaceID, fromID, toID int) error {
+	// Statuses are created lazily, so the new owner may not have any yet.
+	_, err := tx.Exec(`
+		INSERT INTO task_statuses (user_id, name, position, is_done)
+		SELECT $2, name, position, is_done FROM task_statuses
+		WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM task_statuses WHERE user_id = $2)
+	`, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to copy statuses: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO tags (user_id, name, color)
+		SELECT DISTINCT $3::int, g.name, g.color
+		FROM tags g
+		JOIN task_tags tt ON tt.tag_id = g.id
+		JOIN tasks t ON t.id = tt.task_id
+		WHERE t.workspace_id = $1 AND t.user_id = $2
+		ON CONFLICT (user_id, name) DO NOTHING
+	`, workspaceID, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to copy tags: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE task_tags tt SET tag_id = nt.id
+		FROM tasks t, tags ot, tags nt
+		WHERE t.id = tt.task_id AND t.workspace_id = $1 AND t.user_id = $2
+			AND ot.id = tt.tag_id AND nt.user_id = $3 AND nt.name = ot.name
+	`, workspaceID, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to move task tags: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`UPDATE projects SET user_id = $3, updated_at = CURRENT_TIMESTAMP WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID, fromID, toID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to hand over projects: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE tasks t
+		SET user_id = $3, status_id = (
+			SELECT ns.id FROM task_statuses os, task_statuses ns
+			WHERE os.id = t.status_id AND ns.user_id = $3 AND ns.is_done = os.is_done
+			ORDER BY LOWER(ns.name) = LOWER(os.name) DESC, ns.position, ns.id
+			LIMIT 1
+		)
+		WHERE t.workspace_id = $1 AND t.user_id = $2
+	`, workspaceID, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to hand over tasks: %w", err)
+	}
+
+	return nil
+}
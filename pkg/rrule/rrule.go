@@ -0,0 +1,469 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules YouDo
+// supports: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY,
+// BYMONTHDAY (with FREQ=MONTHLY), COUNT and UNTIL. Weeks start on Monday.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxSearch bounds the number of periods searched for the next occurrence,
+// e.g. for a rule on February 29th or the fifth Friday of a month.
+const maxSearch = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+	time.Sunday:    "SU",
+}
+
+// Weekday is a BYDAY entry. N selects the nth weekday of the month (negative
+// counts from the end) and is only allowed with FREQ=MONTHLY; zero means
+// every such weekday.
+type Weekday struct {
+	Day time.Weekday
+	N   int
+}
+
+func (w Weekday) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Day]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Day]
+}
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []Weekday
+	// ByMonthDay selects days of the month; negative days count from the
+	// end, so -1 is the last day. Months without the day are skipped.
+	ByMonthDay []int
+	// Count is the number of occurrences left, including the current one.
+	// Zero means unlimited.
+	Count int
+	// Until is the last allowed occurrence. The zero time means no limit.
+	Until time.Time
+	// untilFloating is set when UNTIL had no time zone; it is then read as
+	// a wall-clock time in the location of the occurrences.
+	untilFloating bool
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH".
+// A leading "RRULE:" is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("empty recurrence rule")
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		key = strings.ToUpper(strings.TrimSpace(key))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate %s in recurrence rule", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(val)
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %s", val)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive number")
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("COUNT must be a positive number")
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, floating, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = until
+			rule.untilFloating = floating
+		case "BYMONTHDAY":
+			for _, item := range strings.Split(val, ",") {
+				day, err := strconv.Atoi(item)
+				if err != nil || day == 0 || day < -31 || day > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %s", item)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, day)
+			}
+		case "BYDAY":
+			for _, item := range strings.Split(val, ",") {
+				day, err := parseWeekday(item)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("recurrence rule needs FREQ")
+	}
+
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+
+	if rule.Freq == Yearly && len(rule.ByDay) > 0 {
+		return nil, fmt.Errorf("BYDAY is not supported with FREQ=YEARLY")
+	}
+
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return nil, fmt.Errorf("BYMONTHDAY needs FREQ=MONTHLY")
+	}
+
+	if len(rule.ByMonthDay) > 0 && len(rule.ByDay) > 0 {
+		return nil, fmt.Errorf("BYDAY and BYMONTHDAY cannot be combined")
+	}
+
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly {
+			return nil, fmt.Errorf("numbered BYDAY values need FREQ=MONTHLY")
+		}
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		// A date includes the whole day.
+		return t.Add(24*time.Hour - time.Second), true, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid UNTIL %s", value)
+}
+
+func parseWeekday(value string) (Weekday, error) {
+	if len(value) < 2 {
+		return Weekday{}, fmt.Errorf("invalid BYDAY %s", value)
+	}
+
+	day, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return Weekday{}, fmt.Errorf("invalid BYDAY %s", value)
+	}
+
+	n := 0
+	if prefix := value[:len(value)-2]; prefix != "" {
+		parsed, err := strconv.Atoi(prefix)
+		if err != nil || parsed == 0 || parsed < -5 || parsed > 5 {
+			return Weekday{}, fmt.Errorf("invalid BYDAY %s", value)
+		}
+		n = parsed
+	}
+
+	return Weekday{Day: day, N: n}, nil
+}
+
+// String returns the rule in canonical RRULE form.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if !r.Until.IsZero() {
+		if r.untilFloating {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence after prev, which must itself be an
+// occurrence of the rule. Dates are calculated on the wall clock of prev's
+// location, so an occurrence keeps its local time across DST changes. The
+// returned rule belongs to the next occurrence: its COUNT is one lower. ok
+// is false when the series has ended.
+func (r *Rule) Next(prev time.Time) (next time.Time, rule *Rule, ok bool) {
+	if r.Count == 1 {
+		return time.Time{}, nil, false
+	}
+
+	switch r.Freq {
+	case Daily:
+		next, ok = r.nextDaily(prev)
+	case Weekly:
+		next, ok = r.nextWeekly(prev)
+	case Monthly:
+		next, ok = r.nextMonthly(prev)
+	case Yearly:
+		next, ok = r.nextYearly(prev)
+	}
+
+	if !ok || r.pastUntil(next) {
+		return time.Time{}, nil, false
+	}
+
+	following := *r
+	following.ByDay = append([]Weekday(nil), r.ByDay...)
+	following.ByMonthDay = append([]int(nil), r.ByMonthDay...)
+	if following.Count > 0 {
+		following.Count--
+	}
+
+	return next, &following, true
+}
+
+func (r *Rule) pastUntil(t time.Time) bool {
+	if r.Until.IsZero() {
+		return false
+	}
+
+	until := r.Until
+	if r.untilFloating {
+		until = wallClock(t.Location(), until.Year(), until.Month(), until.Day(), until)
+	}
+
+	return t.After(until)
+}
+
+func (r *Rule) nextDaily(prev time.Time) (time.Time, bool) {
+	for i := 1; i <= maxSearch; i++ {
+		next := addDays(prev, i*r.Interval)
+		if r.matchesWeekday(next.Weekday()) {
+			return next, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (r *Rule) nextWeekly(prev time.Time) (time.Time, bool) {
+	offsets := r.weekOffsets(prev.Weekday())
+	weekStart := addDays(prev, -mondayOffset(prev.Weekday()))
+
+	for _, offset := range offsets {
+		if next := addDays(weekStart, offset); next.After(prev) {
+			return next, true
+		}
+	}
+
+	return addDays(weekStart, 7*r.Interval+offsets[0]), true
+}
+
+func (r *Rule) nextMonthly(prev time.Time) (time.Time, bool) {
+	year, month, day := prev.Date()
+	byDate := len(r.ByDay) == 0 && len(r.ByMonthDay) == 0
+
+	for i := 0; i <= maxSearch; i++ {
+		if i == 0 && byDate {
+			continue
+		}
+
+		// Normalise the month first so day overflow cannot skip a month.
+		first := time.Date(year, month+time.Month(i*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+
+		if byDate {
+			// Months without this day are skipped, as RFC 5545 requires.
+			if day <= daysIn(first.Year(), first.Month()) {
+				return wallClock(prev.Location(), first.Year(), first.Month(), day, prev), true
+			}
+			continue
+		}
+
+		for _, d := range r.monthDays(first.Year(), first.Month()) {
+			next := wallClock(prev.Location(), first.Year(), first.Month(), d, prev)
+			if next.After(prev) {
+				return next, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (r *Rule) nextYearly(prev time.Time) (time.Time, bool) {
+	year, month, day := prev.Date()
+
+	for i := 1; i <= maxSearch; i++ {
+		// February 29th only recurs in leap years.
+		y := year + i*r.Interval
+		if day <= daysIn(y, month) {
+			return wallClock(prev.Location(), y, month, day, prev), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (r *Rule) matchesWeekday(day time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	for _, d := range r.ByDay {
+		if d.Day == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// weekOffsets returns the sorted BYDAY offsets from Monday, defaulting to
+// the weekday of the first occurrence.
+func (r *Rule) weekOffsets(fallback time.Weekday) []int {
+	if len(r.ByDay) == 0 {
+		return []int{mondayOffset(fallback)}
+	}
+
+	seen := make(map[int]bool)
+	offsets := []int{}
+	for _, d := range r.ByDay {
+		offset := mondayOffset(d.Day)
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Ints(offsets)
+
+	return offsets
+}
+
+// monthDays returns the sorted days of the month selected by BYDAY or
+// BYMONTHDAY.
+func (r *Rule) monthDays(year int, month time.Month) []int {
+	total := daysIn(year, month)
+	firstWeekday := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+
+	selected := make(map[int]bool)
+	for _, d := range r.ByMonthDay {
+		day := d
+		if d < 0 {
+			day = total + 1 + d
+		}
+		if day >= 1 && day <= total {
+			selected[day] = true
+		}
+	}
+
+	for _, d := range r.ByDay {
+		first := 1 + (int(d.Day)-int(firstWeekday)+7)%7
+
+		var matches []int
+		for day := first; day <= total; day += 7 {
+			matches = append(matches, day)
+		}
+
+		switch {
+		case d.N == 0:
+			for _, day := range matches {
+				selected[day] = true
+			}
+		case d.N > 0 && d.N <= len(matches):
+			selected[matches[d.N-1]] = true
+		case d.N < 0 && -d.N <= len(matches):
+			selected[matches[len(matches)+d.N]] = true
+		}
+	}
+
+	days := make([]int, 0, len(selected))
+	for day := range selected {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+
+	return days
+}
+
+func mondayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func addDays(t time.Time, days int) time.Time {
+	return t.AddDate(0, 0, days)
+}
+
+// wallClock returns the given date at the time of day of clock in loc.
+func wallClock(loc *time.Location, year int, month time.Month, day int, clock time.Time) time.Time {
+	return time.Date(year, month, day, clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), loc)
+}
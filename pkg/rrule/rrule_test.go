@@ -0,0 +1,347 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+// series returns start followed by up to limit-1 further occurrences.
+func series(t *testing.T, value string, start time.Time, limit int) []time.Time {
+	t.Helper()
+
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", value, err)
+	}
+
+	occurrences := []time.Time{start}
+	for len(occurrences) < limit {
+		next, following, ok := rule.Next(occurrences[len(occurrences)-1])
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, next)
+		rule = following
+	}
+
+	return occurrences
+}
+
+func TestNext(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	utc := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	in := func(loc *time.Location, year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		limit int
+		want  []time.Time
+	}{
+		{
+			name:  "monthly on the 31st skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: utc(2025, time.January, 31, 9),
+			limit: 5,
+			want: []time.Time{
+				utc(2025, time.January, 31, 9),
+				utc(2025, time.March, 31, 9),
+				utc(2025, time.May, 31, 9),
+				utc(2025, time.July, 31, 9),
+				utc(2025, time.August, 31, 9),
+			},
+		},
+		{
+			name:  "BYMONTHDAY=31 skips short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: utc(2025, time.January, 31, 9),
+			limit: 4,
+			want: []time.Time{
+				utc(2025, time.January, 31, 9),
+				utc(2025, time.March, 31, 9),
+				utc(2025, time.May, 31, 9),
+				utc(2025, time.July, 31, 9),
+			},
+		},
+		{
+			name:  "BYMONTHDAY=31 from earlier in the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: utc(2025, time.April, 10, 9),
+			limit: 2,
+			want: []time.Time{
+				utc(2025, time.April, 10, 9),
+				utc(2025, time.May, 31, 9),
+			},
+		},
+		{
+			name:  "BYMONTHDAY=-1 is the last day of every month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: utc(2024, time.January, 31, 9),
+			limit: 4,
+			want: []time.Time{
+				utc(2024, time.January, 31, 9),
+				utc(2024, time.February, 29, 9),
+				utc(2024, time.March, 31, 9),
+				utc(2024, time.April, 30, 9),
+			},
+		},
+		{
+			name:  "BYMONTHDAY with several days",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=15,1",
+			start: utc(2025, time.January, 1, 9),
+			limit: 4,
+			want: []time.Time{
+				utc(2025, time.January, 1, 9),
+				utc(2025, time.January, 15, 9),
+				utc(2025, time.February, 1, 9),
+				utc(2025, time.February, 15, 9),
+			},
+		},
+		{
+			name:  "yearly on February 29th only recurs in leap years",
+			rule:  "FREQ=YEARLY",
+			start: utc(2024, time.February, 29, 9),
+			limit: 3,
+			want: []time.Time{
+				utc(2024, time.February, 29, 9),
+				utc(2028, time.February, 29, 9),
+				utc(2032, time.February, 29, 9),
+			},
+		},
+		{
+			name:  "yearly on February 29th with INTERVAL",
+			rule:  "FREQ=YEARLY;INTERVAL=3",
+			start: utc(2024, time.February, 29, 9),
+			limit: 2,
+			want: []time.Time{
+				utc(2024, time.February, 29, 9),
+				utc(2036, time.February, 29, 9),
+			},
+		},
+		{
+			name:  "daily keeps the local time when DST starts",
+			rule:  "FREQ=DAILY",
+			start: in(newYork, 2025, time.March, 8, 9),
+			limit: 3,
+			want: []time.Time{
+				in(newYork, 2025, time.March, 8, 9),
+				in(newYork, 2025, time.March, 9, 9),
+				in(newYork, 2025, time.March, 10, 9),
+			},
+		},
+		{
+			name:  "daily keeps the local time when DST ends",
+			rule:  "FREQ=DAILY",
+			start: in(newYork, 2025, time.November, 1, 9),
+			limit: 2,
+			want: []time.Time{
+				in(newYork, 2025, time.November, 1, 9),
+				in(newYork, 2025, time.November, 2, 9),
+			},
+		},
+		{
+			name:  "weekly keeps the local time across DST",
+			rule:  "FREQ=WEEKLY",
+			start: in(berlin, 2025, time.March, 24, 8),
+			limit: 2,
+			want: []time.Time{
+				in(berlin, 2025, time.March, 24, 8),
+				in(berlin, 2025, time.March, 31, 8),
+			},
+		},
+		{
+			name:  "monthly keeps the local time across DST",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: in(berlin, 2025, time.February, 28, 8),
+			limit: 2,
+			want: []time.Time{
+				in(berlin, 2025, time.February, 28, 8),
+				in(berlin, 2025, time.March, 31, 8),
+			},
+		},
+		{
+			name:  "weekly BYDAY with INTERVAL skips a week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: utc(2025, time.January, 6, 9),
+			limit: 5,
+			want: []time.Time{
+				utc(2025, time.January, 6, 9),
+				utc(2025, time.January, 9, 9),
+				utc(2025, time.January, 20, 9),
+				utc(2025, time.January, 23, 9),
+				utc(2025, time.February, 3, 9),
+			},
+		},
+		{
+			name:  "monthly last Friday with INTERVAL",
+			rule:  "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR",
+			start: utc(2025, time.January, 31, 9),
+			limit: 3,
+			want: []time.Time{
+				utc(2025, time.January, 31, 9),
+				utc(2025, time.March, 28, 9),
+				utc(2025, time.May, 30, 9),
+			},
+		},
+		{
+			name:  "daily BYDAY with INTERVAL",
+			rule:  "FREQ=DAILY;INTERVAL=2;BYDAY=MO",
+			start: utc(2025, time.January, 6, 9),
+			limit: 2,
+			want: []time.Time{
+				utc(2025, time.January, 6, 9),
+				utc(2025, time.January, 20, 9),
+			},
+		},
+		{
+			name:  "COUNT includes the first occurrence",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: utc(2025, time.January, 1, 9),
+			limit: 10,
+			want: []time.Time{
+				utc(2025, time.January, 1, 9),
+				utc(2025, time.January, 2, 9),
+				utc(2025, time.January, 3, 9),
+			},
+		},
+		{
+			name:  "COUNT=1 has no further occurrences",
+			rule:  "FREQ=WEEKLY;COUNT=1",
+			start: utc(2025, time.January, 1, 9),
+			limit: 10,
+			want: []time.Time{
+				utc(2025, time.January, 1, 9),
+			},
+		},
+		{
+			name:  "UTC UNTIL includes an occurrence at that instant",
+			rule:  "FREQ=WEEKLY;UNTIL=20250120T090000Z",
+			start: utc(2025, time.January, 6, 9),
+			limit: 10,
+			want: []time.Time{
+				utc(2025, time.January, 6, 9),
+				utc(2025, time.January, 13, 9),
+				utc(2025, time.January, 20, 9),
+			},
+		},
+		{
+			name:  "UTC UNTIL compares instants in other zones",
+			rule:  "FREQ=WEEKLY;UNTIL=20250120T130000Z",
+			start: in(newYork, 2025, time.January, 6, 9),
+			limit: 10,
+			want: []time.Time{
+				in(newYork, 2025, time.January, 6, 9),
+				in(newYork, 2025, time.January, 13, 9),
+			},
+		},
+		{
+			name:  "UNTIL date includes the whole local day",
+			rule:  "FREQ=WEEKLY;UNTIL=20250120",
+			start: in(newYork, 2025, time.January, 6, 23),
+			limit: 10,
+			want: []time.Time{
+				in(newYork, 2025, time.January, 6, 23),
+				in(newYork, 2025, time.January, 13, 23),
+				in(newYork, 2025, time.January, 20, 23),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := series(t, tt.rule, tt.start, tt.limit)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) || got[i].Location() != tt.want[i].Location() {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextDecrementsCount(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, next, ok := rule.Next(time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC))
+	if !ok {
+		t.Fatal("Next ended the series early")
+	}
+	if next.Count != 2 || rule.Count != 3 {
+		t.Errorf("counts = %d and %d, want 2 for the next rule and 3 for the original", next.Count, rule.Count)
+	}
+	if got := next.String(); got != "FREQ=DAILY;COUNT=2" {
+		t.Errorf("next rule = %s, want FREQ=DAILY;COUNT=2", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"},
+		{value: "freq=monthly;byday=-1fr", want: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=31,-1", want: "FREQ=MONTHLY;BYMONTHDAY=31,-1"},
+		{value: "FREQ=DAILY;INTERVAL=1;COUNT=5", want: "FREQ=DAILY;COUNT=5"},
+		{value: "FREQ=WEEKLY;UNTIL=20250120T090000Z", want: "FREQ=WEEKLY;UNTIL=20250120T090000Z"},
+		{value: "FREQ=WEEKLY;UNTIL=20250120T090000", want: "FREQ=WEEKLY;UNTIL=20250120T090000"},
+		{value: "", wantErr: true},
+		{value: "INTERVAL=2", wantErr: true},
+		{value: "FREQ=HOURLY", wantErr: true},
+		{value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{value: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=2;UNTIL=20250120", wantErr: true},
+		{value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{value: "FREQ=MONTHLY;BYDAY=6MO", wantErr: true},
+		{value: "FREQ=YEARLY;BYDAY=MO", wantErr: true},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=0", wantErr: true},
+		{value: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=MO", wantErr: true},
+		{value: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		rule, err := Parse(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %s, want an error", tt.value, rule)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+// ErrForbidden is returned when a user can see a task or project but their
+// role does not allow the requested change.
+var ErrForbidden = errors.New("permission denied")
+
+// AccessService decides who may read or change tasks and projects and
+// manages the shares that grant access to other users. Members of the
+// workspace of an item can edit it, and its admins and owner manage it like
+// the item's owner. Every check reads the shares and memberships from the
+// database, so a revoked share or a removed member loses access on the
+// next request.
+//
+// Authorizing returns the owner of the item. Repositories keep scoping
+// their queries to that owner, so a task can only be linked to other tasks,
+// tags, statuses and projects of the same owner. Reminders need read
+// access to the task and stay private to the user who set them. The trash
+// of a workspace is visible to its members; restoring a task needs the
+// editor role and purging it the owner role, like deleting and managing it.
+type AccessService struct {
+	shareRepo *repository.ShareRepository
+	userRepo  *repository.UserRepository
+}
+
+func NewAccessService(shareRepo *repository.ShareRepository, userRepo *repository.UserRepository) *AccessService {
+	return &AccessService{shareRepo: shareRepo, userRepo: userRepo}
+}
+
+// Task checks that the user has at least the required role on a task and
+// returns its owner.
+func (a *AccessService) Task(taskID, userID int, required model.Role) (int, error) {
+	ownerID, role, err := a.shareRepo.GetTaskAccess(taskID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !role.Allows(required) {
+		return 0, ErrForbidden
+	}
+
+	return ownerID, nil
+}
+
+// Tasks does what Task does for many tasks with one query and returns
+// their owners keyed by task ID. A repository.TaskItemError names the
+// first task the check failed for.
+func (a *AccessService) Tasks(taskIDs []int, userID int, required model.Role) (map[int]int, error) {
+	access, err := a.shareRepo.GetTasksAccess(taskIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerIDs := make(map[int]int, len(taskIDs))
+	for _, id := range taskIDs {
+		item, ok := access[id]
+		if !ok {
+			return nil, &repository.TaskItemError{TaskID: id, Err: fmt.Errorf("task not found")}
+		}
+		if !item.Role.Allows(required) {
+			return nil, &repository.TaskItemError{TaskID: id, Err: ErrForbidden}
+		}
+		ownerIDs[id] = item.OwnerID
+	}
+
+	return ownerIDs, nil
+}
+
+// Project checks that the user has at least the required role on a
+// project and returns its owner.
+func (a *AccessService) Project(projectID, userID int, required model.Role) (int, error) {
+	ownerID, role, err := a.shareRepo.GetProjectAccess(projectID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !role.Allows(required) {
+		return 0, ErrForbidden
+	}
+
+	return ownerID, nil
+}
+
+// ShareTask gives the user with the requested email access to a task and
+// its subtasks. Only the owner and the admins of its workspace can share.
+func (a *AccessService) ShareTask(taskID, userID int, req *dto.ShareRequest) (*dto.ShareResponse, error) {
+	if _, err := a.Task(taskID, userID, model.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	recipient, err := a.recipient(userID, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.shareRepo.ShareTask(taskID, recipient.ID, model.Role(req.Role), userID); err != nil {
+		return nil, err
+	}
+
+	shares, err := a.shareRepo.GetTaskShares(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shareOf(shares, recipient.ID)
+}
+
+func (a *AccessService) GetTaskShares(taskID, userID int) ([]dto.ShareResponse, error) {
+	if _, err := a.Task(taskID, userID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	shares, err := a.shareRepo.GetTaskShares(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toShareResponses(shares), nil
+}
+
+// UnshareTask revokes the access of recipientID to a task. Whoever can
+// share the task can revoke any share and recipients can remove their own.
+func (a *AccessService) UnshareTask(taskID, userID, recipientID int) error {
+	required := model.RoleOwner
+	if recipientID == userID {
+		required = model.RoleViewer
+	}
+
+	if _, err := a.Task(taskID, userID, required); err != nil {
+		return err
+	}
+
+	return a.shareRepo.UnshareTask(taskID, recipientID)
+}
+
+// ShareProject gives the user with the requested email access to a project
+// and all of its tasks. Only the owner and the admins of its workspace can share.
+func (a *AccessService) ShareProject(projectID, userID int, req *dto.ShareRequest) (*dto.ShareResponse, error) {
+	if _, err := a.Project(projectID, userID, model.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	recipient, err := a.recipient(userID, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.shareRepo.ShareProject(projectID, recipient.ID, model.Role(req.Role), userID); err != nil {
+		return nil, err
+	}
+
+	shares, err := a.shareRepo.GetProjectShares(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shareOf(shares, recipient.ID)
+}
+
+func (a *AccessService) GetProjectShares(projectID, userID int) ([]dto.ShareResponse, error) {
+	if _, err := a.Project(projectID, userID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	shares, err := a.shareRepo.GetProjectShares(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toShareResponses(shares), nil
+}
+
+// UnshareProject revokes the access of recipientID to a project. Whoever
+// can share the project can revoke any share and recipients can remove
+// their own.
+func (a *AccessService) UnshareProject(projectID, userID, recipientID int) error {
+	required := model.RoleOwner
+	if recipientID == userID {
+		required = model.RoleViewer
+	}
+
+	if _, err := a.Project(projectID, userID, required); err != nil {
+		return err
+	}
+
+	return a.shareRepo.UnshareProject(projectID, recipientID)
+}
+
+// recipient looks up the registered user an item is shared with.
+func (a *AccessService) recipient(ownerID int, email string) (*model.User, error) {
+	user, err := a.userRepo.GetByEmail(utils.SanitizeString(email))
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID == ownerID {
+		return nil, fmt.Errorf("you cannot share with yourself")
+	}
+
+	return user, nil
+}
+
+// shareOf returns the share of one user.
+func shareOf(shares []model.Share, userID int) (*dto.ShareResponse, error) {
+	for i := range shares {
+		if shares[i].UserID == userID {
+			return toShareResponse(&shares[i]), nil
+		}
+	}
+
+	return nil, fmt.Errorf("share not found")
+}
+
+func toShareResponse(share *model.Share) *dto.ShareResponse {
+	return &dto.ShareResponse{
+		UserID:    share.UserID,
+		Email:     share.Email,
+		Name:      share.Name,
+		Role:      string(share.Role),
+		CreatedAt: share.CreatedAt,
+	}
+}
+
+func toShareResponses(shares []model.Share) []dto.ShareResponse {
+	responses := make([]dto.ShareResponse, len(shares))
+	for i := range shares {
+		responses[i] = *toShareResponse(&shares[i])
+	}
+	return responses
+}
@@ -1,26 +1,60 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/mailer"
 	"github.com/faisal-amiruddin/YouDo/pkg/model"
 	"github.com/faisal-amiruddin/YouDo/pkg/repository"
 	"github.com/faisal-amiruddin/YouDo/pkg/utils"
 )
 
+// AuthConfig holds the settings AuthService needs from config.Config.
+type AuthConfig struct {
+	JWTSecret                string
+	JWTExpiry                time.Duration
+	RefreshExpiry            time.Duration
+	PasswordResetExpiry      time.Duration
+	EmailVerificationExpiry  time.Duration
+	TwoFactorChallengeExpiry time.Duration
+	FrontendURL              string
+	PublicURL                string
+}
+
 type AuthService struct {
-	userRepo  *repository.UserRepository
-	jwtSecret string
-	jwtExpiry string
+	userRepo         *repository.UserRepository
+	tokenRepo        *repository.TokenRepository
+	resetRepo        *repository.PasswordResetRepository
+	verifyRepo       *repository.EmailVerificationRepository
+	twoFactorService *TwoFactorService
+	loginThrottle    *LoginThrottle
+	mailer           mailer.Mailer
+	cfg              AuthConfig
 }
 
-func NewAuthService(userRepo *repository.UserRepository, jwtSecret, jwtExpiry string) *AuthService {
+func NewAuthService(
+	userRepo *repository.UserRepository,
+	tokenRepo *repository.TokenRepository,
+	resetRepo *repository.PasswordResetRepository,
+	verifyRepo *repository.EmailVerificationRepository,
+	twoFactorService *TwoFactorService,
+	loginThrottle *LoginThrottle,
+	mailSender mailer.Mailer,
+	cfg AuthConfig,
+) *AuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		jwtExpiry: jwtExpiry,
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		resetRepo:        resetRepo,
+		verifyRepo:       verifyRepo,
+		twoFactorService: twoFactorService,
+		loginThrottle:    loginThrottle,
+		mailer:           mailSender,
+		cfg:              cfg,
 	}
 }
 
@@ -56,44 +90,384 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, err
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	expiry, _ := time.ParseDuration(s.jwtExpiry)
-	token, err := utils.GenerateToken(user.ID, user.Email, s.jwtSecret, expiry)
+	if err := s.SendVerificationEmail(user); err != nil {
+		utils.Error("Failed to send verification mail to user %d: %v", user.ID, err)
+	}
+
+	return s.issueTokens(user, "")
+}
+
+// Login checks the password. Users with two-factor auth enabled get a
+// challenge instead of tokens, to be completed with LoginTwoFactor. Failed
+// attempts are throttled per account and per client address; the returned
+// error is then a *LoginThrottleError.
+func (s *AuthService) Login(req *dto.LoginRequest, ip string) (*dto.AuthResponse, *dto.TwoFactorChallengeResponse, error) {
+	if err := s.loginThrottle.Check(req.Email, ip); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil {
+		return nil, nil, s.loginThrottle.RecordFailure(req.Email, ip, 0)
+	}
+
+	if err := utils.CheckPassword(user.PasswordHash, req.Password); err != nil {
+		return nil, nil, s.loginThrottle.RecordFailure(req.Email, ip, user.ID)
+	}
+
+	// With two-factor auth the failures are only cleared once the second
+	// factor passes, so a known password cannot reset the code throttle.
+	if !user.TOTPEnabledAt.Valid {
+		if err := s.loginThrottle.RecordSuccess(req.Email); err != nil {
+			utils.Error("Failed to reset login failures for user %d: %v", user.ID, err)
+		}
+	}
+
+	return s.CompleteLogin(user)
+}
+
+// CompleteLogin finishes a login for an authenticated user, issuing tokens or
+// a two-factor challenge.
+func (s *AuthService) CompleteLogin(user *model.User) (*dto.AuthResponse, *dto.TwoFactorChallengeResponse, error) {
+	if user.TOTPEnabledAt.Valid {
+		challenge, err := utils.GenerateChallengeToken(user.ID, s.cfg.JWTSecret, s.cfg.TwoFactorChallengeExpiry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+		}
+
+		return nil, &dto.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresAt:         time.Now().Add(s.cfg.TwoFactorChallengeExpiry),
+		}, nil
+	}
+
+	response, err := s.issueTokens(user, "")
+	return response, nil, err
+}
+
+// IssueReauthToken returns a short-lived proof that the user has just signed
+// in again. Accounts without a password use it to confirm sensitive
+// changes. It expires together with a two-factor challenge.
+func (s *AuthService) IssueReauthToken(userID int) (*dto.ReauthResponse, error) {
+	token, err := utils.GenerateReauthToken(userID, s.cfg.JWTSecret, s.cfg.TwoFactorChallengeExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	return &dto.AuthResponse{
-		Token: token,
-		User: dto.UserResponse{
-			ID:    user.ID,
-			Email: user.Email,
-			Name:  user.Name,
-		},
+	return &dto.ReauthResponse{
+		ReauthToken: token,
+		ExpiresAt:   time.Now().Add(s.cfg.TwoFactorChallengeExpiry),
 	}, nil
 }
 
-func (s *AuthService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
-	user, err := s.userRepo.GetByEmail(req.Email)
+// CheckReauthToken verifies a token issued by IssueReauthToken for the user.
+func (s *AuthService) CheckReauthToken(token string, userID int) error {
+	claims, err := utils.ValidateReauthToken(token, s.cfg.JWTSecret)
+	if err != nil || claims.UserID != userID {
+		return fmt.Errorf("invalid or expired reauth_token")
+	}
+
+	return nil
+}
+
+// LoginTwoFactor completes a login challenge with a TOTP or recovery code.
+// Wrong codes are throttled together with the password attempts of the
+// account and the client address.
+func (s *AuthService) LoginTwoFactor(req *dto.LoginTwoFactorRequest, ip string) (*dto.AuthResponse, error) {
+	claims, err := utils.ValidateChallengeToken(req.ChallengeToken, s.cfg.JWTSecret)
 	if err != nil {
-		return nil, fmt.Errorf("invalid email or password")
+		return nil, fmt.Errorf("invalid or expired challenge")
 	}
 
-	if err := utils.CheckPassword(user.PasswordHash, req.Password); err != nil {
-		return nil, fmt.Errorf("invalid email or password")
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired challenge")
+	}
+
+	if err := s.loginThrottle.Check(user.Email, ip); err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorService.VerifyCode(user.ID, req.Code); err != nil {
+		if throttleErr := s.loginThrottle.RecordFailure(user.Email, ip, user.ID); throttleErr != nil {
+			var throttled *LoginThrottleError
+			if errors.As(throttleErr, &throttled) && throttled.Locked {
+				return nil, throttleErr
+			}
+		}
+		return nil, err
 	}
 
-	expiry, _ := time.ParseDuration(s.jwtExpiry)
-	token, err := utils.GenerateToken(user.ID, user.Email, s.jwtSecret, expiry)
+	if err := s.loginThrottle.RecordSuccess(user.Email); err != nil {
+		utils.Error("Failed to reset login failures for user %d: %v", user.ID, err)
+	}
+
+	return s.issueTokens(user, "")
+}
+
+func (s *AuthService) Refresh(req *dto.RefreshTokenRequest) (*dto.AuthResponse, error) {
+	current, err := s.tokenRepo.GetRefreshTokenByHash(utils.HashToken(req.RefreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	if current.RevokedAt.Valid {
+		if err := s.tokenRepo.RevokeFamily(current.FamilyID); err != nil {
+			return nil, err
+		}
+		utils.Warn("Refresh token reuse detected for user %d, token family revoked", current.UserID)
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	if time.Now().UTC().After(current.ExpiresAt) {
+		return nil, fmt.Errorf("refresh token expired")
+	}
+
+	user, err := s.userRepo.GetByID(current.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	refreshToken, next, err := s.newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.tokenRepo.RotateRefreshToken(current.ID, next)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rotated {
+		// Another request rotated this token first, so it is being replayed.
+		if err := s.tokenRepo.RevokeFamily(current.FamilyID); err != nil {
+			return nil, err
+		}
+		utils.Warn("Refresh token reuse detected for user %d, token family revoked", current.UserID)
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	return s.buildAuthResponse(user, refreshToken, next.ExpiresAt)
+}
+
+// Logout revokes the whole family of the refresh token and, when a valid
+// access token is given too, denylists it. The refresh token alone is
+// enough, so a client whose access token has expired can still sign out.
+func (s *AuthService) Logout(accessToken string, req *dto.LogoutRequest) error {
+	userID := 0
+	if accessToken != "" {
+		if claims, err := utils.ValidateToken(accessToken, s.cfg.JWTSecret); err == nil {
+			userID = claims.UserID
+			if claims.ID != "" && claims.ExpiresAt != nil {
+				if err := s.tokenRepo.DenyAccessToken(claims.ID, claims.ExpiresAt.Time.UTC()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	token, err := s.tokenRepo.GetRefreshTokenByHash(utils.HashToken(req.RefreshToken))
+	if err != nil || (userID != 0 && token.UserID != userID) {
+		return nil
+	}
+
+	return s.tokenRepo.RevokeFamily(token.FamilyID)
+}
+
+// ForgotPassword e-mails a single-use reset link. The lookup, the token and
+// the mail are handled in the background so that neither the response nor
+// its timing reveals whether the address belongs to an account.
+func (s *AuthService) ForgotPassword(req *dto.ForgotPasswordRequest) {
+	email := utils.SanitizeString(req.Email)
+
+	go func() {
+		if err := s.sendPasswordReset(email); err != nil {
+			utils.Error("Failed to send password reset mail: %v", err)
+		}
+	}()
+}
+
+// sendPasswordReset creates a reset token for the account with the given
+// e-mail address and mails the link. Unknown addresses are ignored.
+func (s *AuthService) sendPasswordReset(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	resetToken := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().UTC().Add(s.cfg.PasswordResetExpiry),
+	}
+
+	if err := s.resetRepo.Create(resetToken); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.cfg.FrontendURL, url.QueryEscape(token))
+	msg := &mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your YouDo password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your YouDo password. Open the link below to choose a new one:\n\n%s\n\nThe link expires in %s and can only be used once. If you did not ask for a reset, you can ignore this e-mail.\n",
+			user.Name, link, s.cfg.PasswordResetExpiry,
+		),
+	}
+
+	if err := s.mailer.Send(msg); err != nil {
+		return fmt.Errorf("failed to send mail to user %d: %w", user.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the user
+// out of every session.
+func (s *AuthService) ResetPassword(req *dto.ResetPasswordRequest) error {
+	if !utils.ValidatePassword(req.Password) {
+		return fmt.Errorf("password must be at least 8 characters and contain letters and numbers")
+	}
+
+	userID, err := s.resetRepo.Consume(utils.HashToken(req.Token))
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(userID, hashedPassword); err != nil {
+		return err
+	}
+
+	return s.tokenRepo.RevokeAllForUser(userID)
+}
+
+// VerifyEmail consumes a verification token and marks the address it was
+// issued for as verified.
+func (s *AuthService) VerifyEmail(token string) error {
+	verification, err := s.verifyRepo.Consume(utils.HashToken(token))
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.MarkEmailVerified(verification.UserID, verification.Email)
+}
+
+func (s *AuthService) ResendVerification(userID int) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		return fmt.Errorf("email already verified")
+	}
+
+	return s.SendVerificationEmail(user)
+}
+
+func (s *AuthService) SendVerificationEmail(user *model.User) error {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	verification := &model.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().UTC().Add(s.cfg.EmailVerificationExpiry),
+	}
+
+	if err := s.verifyRepo.Create(verification); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/api/auth/verify?token=%s", s.cfg.PublicURL, url.QueryEscape(token))
+	msg := &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your YouDo email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s.\n",
+			user.Name, link, s.cfg.EmailVerificationExpiry,
+		),
+	}
+
+	return s.mailer.Send(msg)
+}
+
+// issueTokens creates a new access token and a refresh token. An empty
+// familyID starts a new refresh token family.
+func (s *AuthService) issueTokens(user *model.User, familyID string) (*dto.AuthResponse, error) {
+	refreshToken, record, err := s.newRefreshToken(user.ID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.CreateRefreshToken(record); err != nil {
+		return nil, err
+	}
+
+	return s.buildAuthResponse(user, refreshToken, record.ExpiresAt)
+}
+
+func (s *AuthService) newRefreshToken(userID int, familyID string) (string, *model.RefreshToken, error) {
+	if familyID == "" {
+		id, err := utils.GenerateRandomToken(16)
+		if err != nil {
+			return "", nil, err
+		}
+		familyID = id
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return refreshToken, &model.RefreshToken{
+		UserID:    userID,
+		TokenHash: utils.HashToken(refreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().UTC().Add(s.cfg.RefreshExpiry),
+	}, nil
+}
+
+func (s *AuthService) buildAuthResponse(user *model.User, refreshToken string, refreshExpiresAt time.Time) (*dto.AuthResponse, error) {
+	token, err := utils.GenerateToken(user.ID, user.Email, user.EmailVerifiedAt.Valid, s.cfg.JWTSecret, s.cfg.JWTExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	return &dto.AuthResponse{
-		Token: token,
-		User: dto.UserResponse{
-			ID:    user.ID,
-			Email: user.Email,
-			Name:  user.Name,
-		},
+		Token:            token,
+		ExpiresAt:        time.Now().Add(s.cfg.JWTExpiry),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             toUserResponse(user),
 	}, nil
-}
\ No newline at end of file
+}
+
+func toUserResponse(user *model.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:               user.ID,
+		Email:            user.Email,
+		Name:             user.Name,
+		EmailVerified:    user.EmailVerifiedAt.Valid,
+		TwoFactorEnabled: user.TOTPEnabledAt.Valid,
+	}
+}
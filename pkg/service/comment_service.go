@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/notifier"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+const (
+	defaultCommentListLimit = 50
+	// maxCommentMentions caps the users one comment can mention.
+	maxCommentMentions = 20
+)
+
+// mentionPattern matches @ followed by an e-mail address at the start of
+// the text or after a character that cannot be part of an address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@+-])@([\w.%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// CommentService manages the discussion of tasks. Everyone who can see a
+// task can read and write comments on it; only the author can edit or
+// delete a comment.
+type CommentService struct {
+	commentRepo *repository.CommentRepository
+	taskRepo    *repository.TaskRepository
+	userRepo    *repository.UserRepository
+	access      *AccessService
+	notifier    notifier.Notifier
+}
+
+func NewCommentService(
+	commentRepo *repository.CommentRepository,
+	taskRepo *repository.TaskRepository,
+	userRepo *repository.UserRepository,
+	access *AccessService,
+	commentNotifier notifier.Notifier,
+) *CommentService {
+	return &CommentService{
+		commentRepo: commentRepo,
+		taskRepo:    taskRepo,
+		userRepo:    userRepo,
+		access:      access,
+		notifier:    commentNotifier,
+	}
+}
+
+// CreateComment adds a comment to a task and notifies the users it
+// mentions.
+func (s *CommentService) CreateComment(taskID, userID int, req *dto.CreateCommentRequest) (*dto.CommentResponse, error) {
+	task, err := s.task(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := utils.SanitizeString(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("comment body is required")
+	}
+
+	mentions, err := s.resolveMentions(taskID, body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &model.Comment{
+		TaskID:     task.ID,
+		UserID:     userID,
+		Body:       body,
+		MentionIDs: mentions,
+	}
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(task, comment, mentions)
+
+	return toCommentResponse(comment), nil
+}
+
+// GetComments returns a page of the comments of a task, oldest first.
+func (s *CommentService) GetComments(taskID, userID int, query *dto.CommentListQuery) (*dto.CommentListResponse, error) {
+	if _, err := s.task(taskID, userID); err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultCommentListLimit
+	}
+
+	offset := query.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	comments, total, err := s.commentRepo.GetByTaskID(taskID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.CommentResponse, len(comments))
+	for i := range comments {
+		responses[i] = *toCommentResponse(&comments[i])
+	}
+
+	return &dto.CommentListResponse{
+		Comments: responses,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}, nil
+}
+
+// UpdateComment replaces the body of the user's own comment. Only users
+// who were not mentioned before are notified.
+func (s *CommentService) UpdateComment(taskID, commentID, userID int, req *dto.UpdateCommentRequest) (*dto.CommentResponse, error) {
+	task, err := s.task(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.ownComment(taskID, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := utils.SanitizeString(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("comment body is required")
+	}
+
+	mentions, err := s.resolveMentions(taskID, body)
+	if err != nil {
+		return nil, err
+	}
+
+	mentioned := make(map[int]bool, len(comment.MentionIDs))
+	for _, id := range comment.MentionIDs {
+		mentioned[id] = true
+	}
+
+	var added []int
+	for _, id := range mentions {
+		if !mentioned[id] {
+			added = append(added, id)
+		}
+	}
+
+	comment.Body = body
+	comment.MentionIDs = mentions
+	if err := s.commentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(task, comment, added)
+
+	return toCommentResponse(comment), nil
+}
+
+// DeleteComment removes the user's own comment.
+func (s *CommentService) DeleteComment(taskID, commentID, userID int) error {
+	if _, err := s.task(taskID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.ownComment(taskID, commentID, userID); err != nil {
+		return err
+	}
+
+	return s.commentRepo.Delete(commentID, taskID, userID)
+}
+
+// task loads a task the user can see. Tasks in the trash have no
+// discussion.
+func (s *CommentService) task(taskID, userID int) (*model.Task, error) {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetByID(taskID, ownerID)
+}
+
+func (s *CommentService) ownComment(taskID, commentID, userID int) (*model.Comment, error) {
+	comment, err := s.commentRepo.GetByID(commentID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	return comment, nil
+}
+
+// resolveMentions returns the IDs of the users mentioned in body who can
+// see the task. Addresses of unknown users or users without access are
+// ignored, so a comment does not reveal who has an account.
+func (s *CommentService) resolveMentions(taskID int, body string) ([]int, error) {
+	matches := mentionPattern.FindAllStringSubmatch(body, -1)
+
+	seen := make(map[string]bool)
+	ids := []int{}
+	for _, match := range matches {
+		email := match[1]
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		if len(seen) > maxCommentMentions {
+			return nil, fmt.Errorf("a comment can mention at most %d users", maxCommentMentions)
+		}
+
+		user, err := s.userRepo.GetByEmail(email)
+		if err != nil {
+			continue
+		}
+
+		if _, err := s.access.Task(taskID, user.ID, model.RoleViewer); err != nil {
+			continue
+		}
+
+		ids = append(ids, user.ID)
+	}
+
+	return ids, nil
+}
+
+// notifyMentions tells the mentioned users about a comment. The author is
+// never notified of their own mention, and the comment stands even when a
+// notification cannot be delivered.
+func (s *CommentService) notifyMentions(task *model.Task, comment *model.Comment, userIDs []int) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	author, err := s.userRepo.GetByID(comment.UserID)
+	if err != nil {
+		utils.Error("Failed to notify mentions in comment %d: %v", comment.ID, err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		if userID == comment.UserID {
+			continue
+		}
+
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			utils.Error("Failed to notify mention of user %d in comment %d: %v", userID, comment.ID, err)
+			continue
+		}
+
+		err = s.notifier.Notify(context.Background(), &notifier.Message{
+			UserID:    user.ID,
+			Email:     user.Email,
+			Name:      user.Name,
+			Type:      model.NotificationTypeMention,
+			Title:     fmt.Sprintf("%s mentioned you on %s", author.Name, task.Title),
+			Body:      comment.Body,
+			TaskID:    task.ID,
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			utils.Error("Failed to notify mention of user %d in comment %d: %v", userID, comment.ID, err)
+		}
+	}
+}
+
+func toCommentResponse(comment *model.Comment) *dto.CommentResponse {
+	mentionIDs := comment.MentionIDs
+	if mentionIDs == nil {
+		mentionIDs = []int{}
+	}
+
+	return &dto.CommentResponse{
+		ID:         comment.ID,
+		TaskID:     comment.TaskID,
+		UserID:     comment.UserID,
+		Body:       comment.Body,
+		MentionIDs: mentionIDs,
+		Edited:     comment.UpdatedAt.After(comment.CreatedAt),
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+}
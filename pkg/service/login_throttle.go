@@ -0,0 +1,179 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+const (
+	loginScopeAccount = "account"
+	loginScopeIP      = "ip"
+)
+
+// LoginThrottleConfig holds the brute-force protection settings from
+// config.SecurityConfig.
+type LoginThrottleConfig struct {
+	MaxFailures     int
+	IPMaxFailures   int
+	FailureWindow   time.Duration
+	BackoffBase     time.Duration
+	BackoffMax      time.Duration
+	LockoutDuration time.Duration
+}
+
+// LoginThrottleError is returned for a failed or refused login when the
+// client has to wait before the next attempt. Locked is set while attempts
+// are refused without checking the password.
+type LoginThrottleError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottleError) Error() string {
+	if e.Locked {
+		return "too many failed login attempts, try again later"
+	}
+	return "invalid email or password"
+}
+
+// LoginThrottle tracks failed logins per account and per client address.
+// Every failure adds an exponentially growing delay before the next attempt
+// and reaching the limit locks logins for the lockout duration.
+type LoginThrottle struct {
+	failureRepo *repository.LoginFailureRepository
+	auditRepo   *repository.AuditRepository
+	cfg         LoginThrottleConfig
+}
+
+func NewLoginThrottle(
+	failureRepo *repository.LoginFailureRepository,
+	auditRepo *repository.AuditRepository,
+	cfg LoginThrottleConfig,
+) *LoginThrottle {
+	return &LoginThrottle{
+		failureRepo: failureRepo,
+		auditRepo:   auditRepo,
+		cfg:         cfg,
+	}
+}
+
+// Check returns a LoginThrottleError when either the account or the address
+// is still locked.
+func (t *LoginThrottle) Check(email, ip string) error {
+	now := time.Now().UTC()
+
+	var lockedUntil time.Time
+	for _, scope := range [][2]string{{loginScopeAccount, accountKey(email)}, {loginScopeIP, ip}} {
+		until, err := t.failureRepo.GetLockedUntil(scope[0], scope[1])
+		if err != nil {
+			return err
+		}
+		if until.After(lockedUntil) {
+			lockedUntil = until
+		}
+	}
+
+	if lockedUntil.After(now) {
+		return &LoginThrottleError{RetryAfter: lockedUntil.Sub(now), Locked: true}
+	}
+
+	return nil
+}
+
+// RecordFailure registers a failed login and returns the error to report
+// to the client. userID is zero when the email is unknown.
+func (t *LoginThrottle) RecordFailure(email, ip string, userID int) error {
+	accountDelay, accountLocked, err := t.recordFailure(loginScopeAccount, accountKey(email), t.cfg.MaxFailures, userID, ip)
+	if err != nil {
+		return err
+	}
+
+	ipDelay, ipLocked, err := t.recordFailure(loginScopeIP, ip, t.cfg.IPMaxFailures, 0, ip)
+	if err != nil {
+		return err
+	}
+
+	if ipDelay > accountDelay {
+		accountDelay = ipDelay
+	}
+
+	return &LoginThrottleError{RetryAfter: accountDelay, Locked: accountLocked || ipLocked}
+}
+
+// RecordSuccess clears the failures of the account. Failures of the address
+// are kept so one valid account cannot be used to reset them.
+func (t *LoginThrottle) RecordSuccess(email string) error {
+	return t.failureRepo.Reset(loginScopeAccount, accountKey(email))
+}
+
+// recordFailure counts a failure for scope/key, blocks further attempts for
+// the backoff delay and reports whether the lockout limit was reached.
+func (t *LoginThrottle) recordFailure(scope, key string, maxFailures, userID int, ip string) (time.Duration, bool, error) {
+	now := time.Now().UTC()
+
+	count, err := t.failureRepo.RecordFailure(scope, key, now, now.Add(-t.cfg.FailureWindow))
+	if err != nil {
+		return 0, false, err
+	}
+
+	locked := count >= maxFailures
+	delay := t.backoff(count)
+	if locked {
+		delay = t.cfg.LockoutDuration
+		t.audit(scope, key, count, userID, ip)
+	}
+
+	if delay <= 0 {
+		return 0, locked, nil
+	}
+
+	if err := t.failureRepo.Lock(scope, key, now.Add(delay)); err != nil {
+		return 0, false, err
+	}
+
+	return delay, locked, nil
+}
+
+// backoff doubles the delay with every failure, starting at BackoffBase.
+func (t *LoginThrottle) backoff(count int) time.Duration {
+	delay := t.cfg.BackoffBase
+	for i := 1; i < count && delay < t.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+
+	if delay > t.cfg.BackoffMax {
+		delay = t.cfg.BackoffMax
+	}
+
+	return delay
+}
+
+func (t *LoginThrottle) audit(scope, key string, count, userID int, ip string) {
+	event := model.AuditEventLoginLockout
+	if scope == loginScopeIP {
+		event = model.AuditEventLoginIPLockout
+	}
+
+	entry := &model.AuditLog{
+		UserID:    sql.NullInt64{Int64: int64(userID), Valid: userID != 0},
+		Event:     event,
+		IPAddress: ip,
+		Details:   fmt.Sprintf("%s %s locked for %s after %d failed logins", scope, key, t.cfg.LockoutDuration, count),
+	}
+
+	utils.Warn("Login locked: %s", entry.Details)
+
+	if err := t.auditRepo.Create(entry); err != nil {
+		utils.Error("Failed to write audit log: %v", err)
+	}
+}
+
+func accountKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
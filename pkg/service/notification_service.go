@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+const (
+	defaultNotificationListLimit = 20
+	maxNotificationTitleLength   = 255
+)
+
+// NotificationService owns the in-app notifications. Other services create
+// notifications through Create instead of using the repository directly.
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo *repository.NotificationRepository) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+func (s *NotificationService) Create(notification *model.Notification) error {
+	notification.Title = utils.SanitizeString(notification.Title)
+	notification.Body = utils.SanitizeString(notification.Body)
+
+	if notification.Title == "" {
+		return fmt.Errorf("notification title is required")
+	}
+
+	if runes := []rune(notification.Title); len(runes) > maxNotificationTitleLength {
+		notification.Title = string(runes[:maxNotificationTitleLength])
+	}
+
+	return s.notificationRepo.Create(notification)
+}
+
+func (s *NotificationService) GetNotifications(userID int, query *dto.NotificationListQuery) (*dto.NotificationListResponse, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultNotificationListLimit
+	}
+
+	offset := query.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	notifications, total, err := s.notificationRepo.GetAllByUserID(userID, query.UnreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.NotificationResponse, len(notifications))
+	for i := range notifications {
+		responses[i] = toNotificationResponse(&notifications[i])
+	}
+
+	return &dto.NotificationListResponse{
+		Notifications: responses,
+		Total:         total,
+		Unread:        unread,
+		Limit:         limit,
+		Offset:        offset,
+	}, nil
+}
+
+func (s *NotificationService) GetUnreadCount(userID int) (*dto.UnreadCountResponse, error) {
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UnreadCountResponse{Unread: unread}, nil
+}
+
+func (s *NotificationService) MarkRead(notificationID, userID int) error {
+	return s.notificationRepo.MarkRead(notificationID, userID)
+}
+
+func (s *NotificationService) MarkAllRead(userID int) (*dto.MarkAllReadResponse, error) {
+	updated, err := s.notificationRepo.MarkAllRead(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MarkAllReadResponse{Updated: updated}, nil
+}
+
+func (s *NotificationService) DeleteNotification(notificationID, userID int) error {
+	return s.notificationRepo.Delete(notificationID, userID)
+}
+
+func toNotificationResponse(notification *model.Notification) dto.NotificationResponse {
+	response := dto.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		IsRead:    notification.ReadAt.Valid,
+		CreatedAt: notification.CreatedAt,
+	}
+
+	if notification.TaskID.Valid {
+		taskID := int(notification.TaskID.Int64)
+		response.TaskID = &taskID
+	}
+
+	if notification.ReadAt.Valid {
+		response.ReadAt = &notification.ReadAt.Time
+	}
+
+	return response
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/oidc"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+type OIDCService struct {
+	providers    map[string]*oidc.Provider
+	identityRepo *repository.IdentityRepository
+	userRepo     *repository.UserRepository
+	authService  *AuthService
+	stateExpiry  time.Duration
+}
+
+func NewOIDCService(
+	providers []*oidc.Provider,
+	identityRepo *repository.IdentityRepository,
+	userRepo *repository.UserRepository,
+	authService *AuthService,
+	stateExpiry time.Duration,
+) *OIDCService {
+	byName := make(map[string]*oidc.Provider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &OIDCService{
+		providers:    byName,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		stateExpiry:  stateExpiry,
+	}
+}
+
+// BeginLogin starts a sign-in with the given provider.
+func (s *OIDCService) BeginLogin(providerName string) (*dto.OIDCAuthorizationResponse, error) {
+	return s.begin(providerName, sql.NullInt64{}, false)
+}
+
+// BeginLink starts linking a provider account to an existing user.
+func (s *OIDCService) BeginLink(userID int, providerName string) (*dto.OIDCAuthorizationResponse, error) {
+	return s.begin(providerName, sql.NullInt64{Int64: int64(userID), Valid: true}, false)
+}
+
+// BeginReauth starts a fresh sign-in with a provider already linked to the
+// user. The callback returns a reauth token that accounts without a password
+// use to confirm sensitive changes.
+func (s *OIDCService) BeginReauth(userID int, providerName string) (*dto.OIDCAuthorizationResponse, error) {
+	return s.begin(providerName, sql.NullInt64{Int64: int64(userID), Valid: true}, true)
+}
+
+// Callback completes an authorization started by BeginLogin, BeginLink or
+// BeginReauth.
+func (s *OIDCService) Callback(providerName, code, state string) (*dto.OIDCCallbackResponse, error) {
+	provider, err := s.getProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.identityRepo.ConsumeState(utils.HashToken(state), provider.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := provider.Exchange(code, pending.CodeVerifier)
+	if err != nil {
+		utils.Warn("OIDC code exchange with %s failed: %v", provider.Name(), err)
+		return nil, fmt.Errorf("failed to sign in with %s", provider.Name())
+	}
+
+	claims, err := provider.VerifyIDToken(token.IDToken, pending.Nonce)
+	if err != nil {
+		utils.Warn("OIDC id_token from %s rejected: %v", provider.Name(), err)
+		return nil, fmt.Errorf("failed to sign in with %s", provider.Name())
+	}
+
+	if pending.Reauth {
+		identity, err := s.identityRepo.GetByProviderSubject(provider.Name(), claims.Subject)
+		if err != nil || identity.UserID != int(pending.UserID.Int64) {
+			return nil, fmt.Errorf("this %s account is not linked to your account", provider.Name())
+		}
+
+		reauth, err := s.authService.IssueReauthToken(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.OIDCCallbackResponse{Reauth: reauth}, nil
+	}
+
+	if pending.UserID.Valid {
+		identity, err := s.link(int(pending.UserID.Int64), provider.Name(), claims)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.OIDCCallbackResponse{Identity: identity}, nil
+	}
+
+	user, err := s.resolveUser(provider.Name(), claims)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, challenge, err := s.authService.CompleteLogin(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OIDCCallbackResponse{Auth: auth, TwoFactor: challenge}, nil
+}
+
+func (s *OIDCService) ListIdentities(userID int) ([]dto.IdentityResponse, error) {
+	identities, err := s.identityRepo.GetAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.IdentityResponse, len(identities))
+	for i := range identities {
+		responses[i] = toIdentityResponse(&identities[i])
+	}
+
+	return responses, nil
+}
+
+// Unlink removes a linked provider. The last way to sign in cannot be
+// removed, so users without a password keep at least one identity.
+func (s *OIDCService) Unlink(userID int, providerName string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.PasswordHash == "" {
+		identities, err := s.identityRepo.GetAllByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if len(identities) <= 1 {
+			return fmt.Errorf("set a password or link another provider before unlinking your last sign-in provider")
+		}
+	}
+
+	return s.identityRepo.Delete(userID, strings.ToLower(providerName))
+}
+
+func (s *OIDCService) begin(providerName string, userID sql.NullInt64, reauth bool) (*dto.OIDCAuthorizationResponse, error) {
+	provider, err := s.getProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.AuthCodeURL(state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		utils.Error("OIDC provider %s unavailable: %v", provider.Name(), err)
+		return nil, fmt.Errorf("provider %s is unavailable", provider.Name())
+	}
+
+	pending := &model.OIDCState{
+		StateHash:    utils.HashToken(state),
+		Provider:     provider.Name(),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		UserID:       userID,
+		Reauth:       reauth,
+		ExpiresAt:    time.Now().UTC().Add(s.stateExpiry),
+	}
+
+	if err := s.identityRepo.CreateState(pending); err != nil {
+		return nil, err
+	}
+
+	return &dto.OIDCAuthorizationResponse{AuthorizationURL: authURL}, nil
+}
+
+// resolveUser finds the user for a provider login. Unknown identities are
+// linked to the account with the same verified email address, or get a new
+// account when there is none.
+func (s *OIDCService) resolveUser(providerName string, claims *oidc.IDTokenClaims) (*model.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(providerName, claims.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(identity.UserID)
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, fmt.Errorf("%s did not provide a verified email address", providerName)
+	}
+
+	user, err := s.userRepo.GetByEmail(claims.Email)
+	if err == nil {
+		// Only merge into accounts that proved ownership of the address,
+		// otherwise whoever registered it first could be taken over.
+		if !user.EmailVerifiedAt.Valid {
+			return nil, fmt.Errorf("an account with this email already exists, sign in with your password to link %s", providerName)
+		}
+	} else {
+		user, err = s.createUser(claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.link(user.ID, providerName, claims); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *OIDCService) createUser(claims *oidc.IDTokenClaims) (*model.User, error) {
+	name := utils.SanitizeString(claims.Name)
+	if name == "" {
+		name = strings.Split(claims.Email, "@")[0]
+	}
+
+	user := &model.User{
+		Email: utils.SanitizeString(claims.Email),
+		Name:  name,
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := s.userRepo.MarkEmailVerified(user.ID, user.Email); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(user.ID)
+}
+
+func (s *OIDCService) link(userID int, providerName string, claims *oidc.IDTokenClaims) (*dto.IdentityResponse, error) {
+	existing, err := s.identityRepo.GetByProviderSubject(providerName, claims.Subject)
+	if err == nil {
+		if existing.UserID != userID {
+			return nil, fmt.Errorf("this %s account is already linked to another user", providerName)
+		}
+		response := toIdentityResponse(existing)
+		return &response, nil
+	}
+
+	identity := &model.UserIdentity{
+		UserID:   userID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+
+	if err := s.identityRepo.Create(identity); err != nil {
+		if strings.Contains(err.Error(), "user_identities_user_id_provider_key") {
+			return nil, fmt.Errorf("another %s account is already linked", providerName)
+		}
+		return nil, err
+	}
+
+	response := toIdentityResponse(identity)
+	return &response, nil
+}
+
+func (s *OIDCService) getProvider(name string) (*oidc.Provider, error) {
+	provider, ok := s.providers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %s", name)
+	}
+
+	return provider, nil
+}
+
+func toIdentityResponse(identity *model.UserIdentity) dto.IdentityResponse {
+	return dto.IdentityResponse{
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
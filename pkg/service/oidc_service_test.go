@@ -0,0 +1,419 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/oidc"
+	"github.com/faisal-amiruddin/YouDo/pkg/oidc/oidctest"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+const testRedirectURL = "http://localhost/api/auth/oidc/test/callback"
+
+type oidcFixture struct {
+	*userFixture
+	server       *oidctest.Server
+	identityRepo *repository.IdentityRepository
+	auth         *AuthService
+	oidc         *OIDCService
+	users        *UserService
+}
+
+func newOIDCFixture(t *testing.T, stateExpiry time.Duration) *oidcFixture {
+	f := &oidcFixture{userFixture: newUserFixture(t)}
+
+	f.server = oidctest.NewServer("youdo", "secret")
+	t.Cleanup(f.server.Close)
+
+	providers := []*oidc.Provider{
+		oidc.NewProvider(f.server.ProviderConfig("test", testRedirectURL), nil),
+		oidc.NewProvider(f.server.ProviderConfig("other", testRedirectURL), nil),
+	}
+
+	tokenRepo := repository.NewTokenRepository(f.db)
+	twoFactorService := NewTwoFactorService(f.userRepo, repository.NewTwoFactorRepository(f.db))
+	f.identityRepo = repository.NewIdentityRepository(f.db)
+	f.auth = NewAuthService(
+		f.userRepo,
+		tokenRepo,
+		repository.NewPasswordResetRepository(f.db),
+		repository.NewEmailVerificationRepository(f.db),
+		twoFactorService,
+		nil,
+		nil,
+		AuthConfig{
+			JWTSecret:                "test-secret",
+			JWTExpiry:                15 * time.Minute,
+			RefreshExpiry:            24 * time.Hour,
+			TwoFactorChallengeExpiry: 5 * time.Minute,
+		},
+	)
+	f.oidc = NewOIDCService(providers, f.identityRepo, f.userRepo, f.auth, stateExpiry)
+	f.users = NewUserService(f.userRepo, tokenRepo, f.auth, twoFactorService)
+
+	return f
+}
+
+// authorize follows the authorization URL and returns the code and state
+// the provider redirects back with. tamper may change the request first.
+func (f *oidcFixture) authorize(t *testing.T, begin *dto.OIDCAuthorizationResponse, tamper func(url.Values)) (code, state string) {
+	t.Helper()
+
+	authURL := begin.AuthorizationURL
+	if tamper != nil {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query := u.Query()
+		tamper(query)
+		u.RawQuery = query.Encode()
+		authURL = u.String()
+	}
+
+	code, state, err := f.server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+
+	return code, state
+}
+
+func (f *oidcFixture) login(t *testing.T) *dto.OIDCCallbackResponse {
+	t.Helper()
+
+	begin, err := f.oidc.BeginLogin("test")
+	if err != nil {
+		t.Fatalf("BeginLogin failed: %v", err)
+	}
+
+	code, state := f.authorize(t, begin, nil)
+	response, err := f.oidc.Callback("test", code, state)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+
+	return response
+}
+
+func TestOIDCLoginCreatesUser(t *testing.T) {
+	f := newOIDCFixture(t, 10*time.Minute)
+	f.server.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	first := f.login(t)
+	if first.Auth == nil || first.Auth.Token == "" || first.Auth.RefreshToken == "" {
+		t.Fatalf("login returned no tokens: %+v", first)
+	}
+	if !first.Auth.User.EmailVerified || first.Auth.User.Name != "Alice" {
+		t.Errorf("unexpected user %+v", first.Auth.User)
+	}
+
+	user, err := f.userRepo.GetByID(first.Auth.User.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.PasswordHash != "" {
+		t.Error("a user created by a provider sign-in has a password")
+	}
+
+	second := f.login(t)
+	if second.Auth == nil || second.Auth.User.ID != first.Auth.User.ID {
+		t.Errorf("second login signed in user %+v, want %d", second.Auth, first.Auth.User.ID)
+	}
+}
+
+func TestOIDCLoginRequiresVerifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t, 10*time.Minute)
+	f.server.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: false})
+
+	begin, err := f.oidc.BeginLogin("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := f.authorize(t, begin, nil)
+
+	if _, err := f.oidc.Callback("test", code, state); err == nil {
+		t.Error("a sign-in without a verified email created an account")
+	}
+}
+
+func TestOIDCCallbackRejects(t *testing.T) {
+	tests := []struct {
+		name        string
+		stateExpiry time.Duration
+		tamper      func(url.Values)
+		callback    func(t *testing.T, f *oidcFixture, code, state string) error
+		wantUsers   int
+	}{
+		{
+			name: "unknown state",
+			callback: func(t *testing.T, f *oidcFixture, code, state string) error {
+				_, err := f.oidc.Callback("test", code, state+"x")
+				return err
+			},
+		},
+		{
+			name: "reused state",
+			callback: func(t *testing.T, f *oidcFixture, code, state string) error {
+				if _, err := f.oidc.Callback("test", code, state); err != nil {
+					t.Fatalf("first Callback failed: %v", err)
+				}
+				_, err := f.oidc.Callback("test", code, state)
+				return err
+			},
+			wantUsers: 1,
+		},
+		{
+			name: "state of another provider",
+			callback: func(t *testing.T, f *oidcFixture, code, state string) error {
+				_, err := f.oidc.Callback("other", code, state)
+				return err
+			},
+		},
+		{
+			name:        "expired state",
+			stateExpiry: -time.Minute,
+		},
+		{
+			name:   "nonce mismatch",
+			tamper: func(q url.Values) { q.Set("nonce", "another-nonce") },
+		},
+		{
+			name:   "PKCE challenge mismatch",
+			tamper: func(q url.Values) { q.Set("code_challenge", oidc.CodeChallengeS256("another-verifier")) },
+		},
+		{
+			name: "unknown code",
+			callback: func(t *testing.T, f *oidcFixture, code, state string) error {
+				_, err := f.oidc.Callback("test", "unknown", state)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiry := tt.stateExpiry
+			if expiry == 0 {
+				expiry = 10 * time.Minute
+			}
+			f := newOIDCFixture(t, expiry)
+
+			begin, err := f.oidc.BeginLogin("test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			code, state := f.authorize(t, begin, tt.tamper)
+
+			callback := tt.callback
+			if callback == nil {
+				callback = func(t *testing.T, f *oidcFixture, code, state string) error {
+					_, err := f.oidc.Callback("test", code, state)
+					return err
+				}
+			}
+
+			if err := callback(t, f, code, state); err == nil {
+				t.Error("Callback succeeded")
+			}
+			if n := f.count(t, `SELECT COUNT(*) FROM users`); n != tt.wantUsers {
+				t.Errorf("%d users exist, want %d", n, tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestOIDCLinksAccounts(t *testing.T) {
+	f := newOIDCFixture(t, 10*time.Minute)
+
+	t.Run("verified email", func(t *testing.T) {
+		aliceID, _ := f.createUser(t, "alice@example.com")
+		if err := f.userRepo.MarkEmailVerified(aliceID, "alice@example.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		f.server.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true})
+		response := f.login(t)
+		if response.Auth == nil || response.Auth.User.ID != aliceID {
+			t.Fatalf("login signed in %+v, want user %d", response.Auth, aliceID)
+		}
+
+		identity, err := f.identityRepo.GetByProviderSubject("test", "alice")
+		if err != nil || identity.UserID != aliceID {
+			t.Errorf("identity = %+v, %v; want it linked to user %d", identity, err, aliceID)
+		}
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		f.createUser(t, "bob@example.com")
+
+		f.server.SetUser(oidctest.User{Subject: "bob", Email: "bob@example.com", EmailVerified: true})
+		begin, err := f.oidc.BeginLogin("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state := f.authorize(t, begin, nil)
+
+		if _, err := f.oidc.Callback("test", code, state); err == nil {
+			t.Error("a provider sign-in took over an account with an unverified email")
+		}
+		if _, err := f.identityRepo.GetByProviderSubject("test", "bob"); err == nil {
+			t.Error("the identity was linked")
+		}
+	})
+
+	t.Run("explicit link", func(t *testing.T) {
+		carolID, _ := f.createUser(t, "carol@example.com")
+
+		f.server.SetUser(oidctest.User{Subject: "carol-at-provider", Email: "carol@provider.example.com", EmailVerified: true})
+		begin, err := f.oidc.BeginLink(carolID, "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state := f.authorize(t, begin, nil)
+
+		response, err := f.oidc.Callback("test", code, state)
+		if err != nil {
+			t.Fatalf("Callback failed: %v", err)
+		}
+		if response.Identity == nil || response.Auth != nil {
+			t.Fatalf("link returned %+v, want only the identity", response)
+		}
+
+		login := f.login(t)
+		if login.Auth == nil || login.Auth.User.ID != carolID {
+			t.Errorf("login with the linked identity signed in %+v, want user %d", login.Auth, carolID)
+		}
+	})
+
+	t.Run("identity of another user", func(t *testing.T) {
+		daveID, _ := f.createUser(t, "dave@example.com")
+
+		// Already linked to Alice in the first subtest.
+		f.server.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true})
+		begin, err := f.oidc.BeginLink(daveID, "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state := f.authorize(t, begin, nil)
+
+		if _, err := f.oidc.Callback("test", code, state); err == nil {
+			t.Error("an identity linked to another user was linked again")
+		}
+	})
+}
+
+func TestOIDCAccountWithoutPassword(t *testing.T) {
+	f := newOIDCFixture(t, 10*time.Minute)
+	f.server.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	userID := f.login(t).Auth.User.ID
+	claims := &utils.Claims{UserID: userID}
+	newPassword := "newpassword123"
+
+	if err := f.oidc.Unlink(userID, "test"); err == nil || !strings.Contains(err.Error(), "set a password") {
+		t.Errorf("Unlink of the last provider = %v, want an error asking for a password", err)
+	}
+
+	if _, err := f.users.UpdateProfile(userID, &dto.UpdateProfileRequest{Password: &newPassword}); err == nil {
+		t.Error("a password was set without confirmation")
+	}
+	if err := f.users.DeleteAccount(claims, &dto.DeleteAccountRequest{}); err == nil {
+		t.Error("the account was deleted without confirmation")
+	}
+
+	reauth := func() string {
+		t.Helper()
+
+		begin, err := f.oidc.BeginReauth(userID, "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state := f.authorize(t, begin, nil)
+
+		response, err := f.oidc.Callback("test", code, state)
+		if err != nil {
+			t.Fatalf("Callback failed: %v", err)
+		}
+		if response.Reauth == nil || response.Auth != nil || response.Identity != nil {
+			t.Fatalf("reauthentication returned %+v, want only a reauth token", response)
+		}
+
+		return response.Reauth.ReauthToken
+	}
+
+	token := reauth()
+	if err := f.auth.CheckReauthToken(token, userID+1); err == nil {
+		t.Error("a reauth token was accepted for another user")
+	}
+	if err := f.auth.CheckReauthToken("not-a-token", userID); err == nil {
+		t.Error("an invalid reauth token was accepted")
+	}
+
+	if _, err := f.users.UpdateProfile(userID, &dto.UpdateProfileRequest{Password: &newPassword, ReauthToken: token}); err != nil {
+		t.Fatalf("setting a password with a reauth token failed: %v", err)
+	}
+
+	user, err := f.userRepo.GetByID(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.CheckPassword(user.PasswordHash, newPassword); err != nil {
+		t.Error("the new password was not set")
+	}
+
+	// With a password, the password is required again and the
+	// provider can be unlinked.
+	if err := f.users.DeleteAccount(claims, &dto.DeleteAccountRequest{ReauthToken: reauth()}); err == nil {
+		t.Error("an account with a password was deleted without it")
+	}
+	if err := f.oidc.Unlink(userID, "test"); err != nil {
+		t.Errorf("Unlink failed after setting a password: %v", err)
+	}
+}
+
+func TestOIDCReauthDeletesAccount(t *testing.T) {
+	f := newOIDCFixture(t, 10*time.Minute)
+	f.server.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true})
+
+	userID := f.login(t).Auth.User.ID
+
+	t.Run("different provider account", func(t *testing.T) {
+		f.server.SetUser(oidctest.User{Subject: "mallory", Email: "mallory@example.com", EmailVerified: true})
+		defer f.server.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true})
+
+		begin, err := f.oidc.BeginReauth(userID, "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state := f.authorize(t, begin, nil)
+
+		if _, err := f.oidc.Callback("test", code, state); err == nil {
+			t.Error("signing in with another provider account reauthenticated the user")
+		}
+	})
+
+	begin, err := f.oidc.BeginReauth(userID, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := f.authorize(t, begin, nil)
+	response, err := f.oidc.Callback("test", code, state)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+
+	req := &dto.DeleteAccountRequest{ReauthToken: response.Reauth.ReauthToken}
+	if err := f.users.DeleteAccount(&utils.Claims{UserID: userID}, req); err != nil {
+		t.Fatalf("DeleteAccount failed: %v", err)
+	}
+
+	if _, err := f.userRepo.GetByID(userID); err == nil {
+		t.Error("the user still exists")
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+type PersonalTokenService struct {
+	tokenRepo *repository.PersonalTokenRepository
+	userRepo  *repository.UserRepository
+}
+
+func NewPersonalTokenService(tokenRepo *repository.PersonalTokenRepository, userRepo *repository.UserRepository) *PersonalTokenService {
+	return &PersonalTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *PersonalTokenService) CreateToken(userID int, req *dto.CreatePersonalTokenRequest) (*dto.CreatedPersonalTokenResponse, error) {
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("expires_at must be in the future")
+		}
+		expiresAt = sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: true}
+	}
+
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	plain := model.PersonalTokenPrefix + secret
+
+	token := &model.PersonalAccessToken{
+		UserID:      userID,
+		Name:        utils.SanitizeString(req.Name),
+		TokenHash:   utils.HashToken(plain),
+		TokenPrefix: plain[:len(model.PersonalTokenPrefix)+8],
+		Scopes:      uniqueScopes(req.Scopes),
+		ExpiresAt:   expiresAt,
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+
+	return &dto.CreatedPersonalTokenResponse{
+		PersonalTokenResponse: *toPersonalTokenResponse(token),
+		Token:                 plain,
+	}, nil
+}
+
+func (s *PersonalTokenService) ListTokens(userID int) ([]dto.PersonalTokenResponse, error) {
+	tokens, err := s.tokenRepo.GetAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PersonalTokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = *toPersonalTokenResponse(&token)
+	}
+
+	return responses, nil
+}
+
+func (s *PersonalTokenService) RevokeToken(tokenID, userID int) error {
+	return s.tokenRepo.Delete(tokenID, userID)
+}
+
+// AuthenticatePersonalToken resolves a plaintext token to the token record
+// and its owner, and records the use.
+func (s *PersonalTokenService) AuthenticatePersonalToken(plain string) (*model.PersonalAccessToken, *model.User, error) {
+	if !strings.HasPrefix(plain, model.PersonalTokenPrefix) {
+		return nil, nil, fmt.Errorf("invalid token")
+	}
+
+	token, err := s.tokenRepo.GetByHash(utils.HashToken(plain))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid token")
+	}
+
+	if token.ExpiresAt.Valid && time.Now().UTC().After(token.ExpiresAt.Time) {
+		return nil, nil, fmt.Errorf("token expired")
+	}
+
+	user, err := s.userRepo.GetByID(token.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid token")
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(token.ID); err != nil {
+		utils.Warn("Failed to record personal access token usage: %v", err)
+	}
+
+	return token, user, nil
+}
+
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	return result
+}
+
+func toPersonalTokenResponse(token *model.PersonalAccessToken) *dto.PersonalTokenResponse {
+	response := &dto.PersonalTokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.Scopes,
+		CreatedAt:   token.CreatedAt,
+	}
+
+	if token.ExpiresAt.Valid {
+		response.ExpiresAt = &token.ExpiresAt.Time
+	}
+	if token.LastUsedAt.Valid {
+		response.LastUsedAt = &token.LastUsedAt.Time
+	}
+
+	return response
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+const defaultProjectColor = "#808080"
+
+type ProjectService struct {
+	projectRepo *repository.ProjectRepository
+	access      *AccessService
+}
+
+func NewProjectService(projectRepo *repository.ProjectRepository, access *AccessService) *ProjectService {
+	return &ProjectService{projectRepo: projectRepo, access: access}
+}
+
+func (s *ProjectService) CreateProject(userID, workspaceID int, req *dto.CreateProjectRequest) (*dto.ProjectResponse, error) {
+	color := req.Color
+	if color == "" {
+		color = defaultProjectColor
+	}
+
+	project := &model.Project{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Name:        utils.SanitizeString(req.Name),
+		Color:       color,
+	}
+
+	if project.Name == "" {
+		return nil, fmt.Errorf("project name is required")
+	}
+
+	if err := s.projectRepo.Create(project); err != nil {
+		return nil, err
+	}
+
+	return toProjectResponse(project), nil
+}
+
+func (s *ProjectService) GetProjects(userID, workspaceID int, query *dto.ProjectListQuery) ([]dto.ProjectResponse, error) {
+	projects, err := s.projectRepo.GetAllByWorkspace(workspaceID, userID, query.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ProjectResponse, len(projects))
+	for i := range projects {
+		responses[i] = *toProjectResponse(&projects[i])
+	}
+
+	return responses, nil
+}
+
+func (s *ProjectService) GetProject(projectID, userID int) (*dto.ProjectResponse, error) {
+	ownerID, err := s.access.Project(projectID, userID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(projectID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProjectResponse(project), nil
+}
+
+func (s *ProjectService) UpdateProject(projectID, userID int, req *dto.UpdateProjectRequest) (*dto.ProjectResponse, error) {
+	ownerID, err := s.access.Project(projectID, userID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(projectID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		project.Name = utils.SanitizeString(*req.Name)
+		if project.Name == "" {
+			return nil, fmt.Errorf("project name is required")
+		}
+	}
+	if req.Color != nil {
+		project.Color = *req.Color
+	}
+	if req.Archived != nil && *req.Archived != project.ArchivedAt.Valid {
+		if *req.Archived {
+			project.ArchivedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+		} else {
+			project.ArchivedAt = sql.NullTime{}
+		}
+	}
+
+	if err := s.projectRepo.Update(project); err != nil {
+		return nil, err
+	}
+
+	return toProjectResponse(project), nil
+}
+
+// DeleteProject deletes a project and, depending on query.Tasks, either
+// moves its tasks to the inbox (the default) or deletes them too. Only the
+// owner and the admins of its workspace can delete a project.
+func (s *ProjectService) DeleteProject(projectID, userID int, query *dto.DeleteProjectQuery) error {
+	ownerID, err := s.access.Project(projectID, userID, model.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	return s.projectRepo.Delete(projectID, ownerID, query.Tasks == "delete")
+}
+
+func toProjectResponse(project *model.Project) *dto.ProjectResponse {
+	response := &dto.ProjectResponse{
+		ID:             project.ID,
+		OwnerID:        project.UserID,
+		WorkspaceID:    project.WorkspaceID,
+		Name:           project.Name,
+		Color:          project.Color,
+		Archived:       project.ArchivedAt.Valid,
+		OpenTasks:      project.OpenTasks,
+		CompletedTasks: project.CompletedTasks,
+		CreatedAt:      project.CreatedAt,
+		UpdatedAt:      project.UpdatedAt,
+	}
+
+	if project.ArchivedAt.Valid {
+		response.ArchivedAt = &project.ArchivedAt.Time
+	}
+
+	return response
+}
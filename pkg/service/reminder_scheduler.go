@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/notifier"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+// ReminderSchedulerConfig holds the delivery settings from
+// config.ReminderConfig.
+type ReminderSchedulerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryDelay   time.Duration
+}
+
+// ReminderScheduler delivers due reminders through the notifier of their
+// channel. Several instances can run at once: every reminder is locked in
+// the database while it is delivered, so it fires only once.
+type ReminderScheduler struct {
+	reminderRepo *repository.ReminderRepository
+	notifiers    map[model.ReminderChannel]notifier.Notifier
+	cfg          ReminderSchedulerConfig
+}
+
+func NewReminderScheduler(
+	reminderRepo *repository.ReminderRepository,
+	notifiers map[model.ReminderChannel]notifier.Notifier,
+	cfg ReminderSchedulerConfig,
+) *ReminderScheduler {
+	return &ReminderScheduler{
+		reminderRepo: reminderRepo,
+		notifiers:    notifiers,
+		cfg:          cfg,
+	}
+}
+
+// Run polls for due reminders every PollInterval until ctx is cancelled.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil {
+			utils.Error("Reminder scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce delivers up to BatchSize due reminders and returns how many were
+// processed.
+func (s *ReminderScheduler) RunOnce(ctx context.Context) (int, error) {
+	processed := 0
+
+	for processed < s.cfg.BatchSize && ctx.Err() == nil {
+		found, err := s.reminderRepo.ProcessNext(time.Now().UTC(), func(reminder *model.DueReminder) {
+			s.deliver(ctx, reminder)
+		})
+		if err != nil {
+			return processed, err
+		}
+
+		if !found {
+			break
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// deliver sends one reminder and records the outcome on it. Failed
+// deliveries are retried after RetryDelay times the number of attempts until
+// MaxAttempts is reached.
+func (s *ReminderScheduler) deliver(ctx context.Context, reminder *model.DueReminder) {
+	now := time.Now().UTC()
+	reminder.Attempts++
+
+	err := s.send(ctx, reminder)
+	if err == nil {
+		reminder.SentAt = sql.NullTime{Time: now, Valid: true}
+		reminder.LastError = ""
+		return
+	}
+
+	reminder.LastError = err.Error()
+
+	if reminder.Attempts >= s.cfg.MaxAttempts {
+		reminder.FailedAt = sql.NullTime{Time: now, Valid: true}
+		utils.Warn("Reminder %d failed after %d attempts: %v", reminder.ID, reminder.Attempts, err)
+		return
+	}
+
+	retryAt := now.Add(s.cfg.RetryDelay * time.Duration(reminder.Attempts))
+	reminder.FireAt = sql.NullTime{Time: retryAt, Valid: true}
+}
+
+func (s *ReminderScheduler) send(ctx context.Context, reminder *model.DueReminder) error {
+	n, ok := s.notifiers[reminder.Channel]
+	if !ok {
+		return fmt.Errorf("no notifier for channel %s", reminder.Channel)
+	}
+
+	body := fmt.Sprintf("Reminder for your task \"%s\".", reminder.TaskTitle)
+	if reminder.DueDate.Valid {
+		body = fmt.Sprintf("Your task \"%s\" is due %s.", reminder.TaskTitle, reminder.DueDate.Time.UTC().Format("Mon, 02 Jan 2006 15:04 MST"))
+	}
+
+	return n.Notify(ctx, &notifier.Message{
+		UserID:     reminder.UserID,
+		Email:      reminder.Email,
+		Name:       reminder.Name,
+		Type:       model.NotificationTypeReminder,
+		Title:      "Reminder: " + reminder.TaskTitle,
+		Body:       body,
+		TaskID:     reminder.TaskID,
+		WebhookURL: reminder.WebhookURL,
+		CreatedAt:  time.Now().UTC(),
+	})
+}
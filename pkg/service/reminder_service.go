@@ -0,0 +1,175 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/notifier"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+)
+
+// ReminderService manages reminders. Everyone who can see a task can set
+// reminders on it; each user only sees and deletes their own.
+type ReminderService struct {
+	reminderRepo *repository.ReminderRepository
+	taskRepo     *repository.TaskRepository
+	access       *AccessService
+}
+
+func NewReminderService(reminderRepo *repository.ReminderRepository, taskRepo *repository.TaskRepository, access *AccessService) *ReminderService {
+	return &ReminderService{
+		reminderRepo: reminderRepo,
+		taskRepo:     taskRepo,
+		access:       access,
+	}
+}
+
+func (s *ReminderService) CreateReminder(taskID, userID int, req *dto.CreateReminderRequest) (*dto.ReminderResponse, error) {
+	task, err := s.task(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasRemindAt := req.RemindAt != nil && *req.RemindAt != ""
+	if hasRemindAt == (req.OffsetMinutes != nil) {
+		return nil, fmt.Errorf("set either remind_at or offset_minutes")
+	}
+
+	reminder := &model.Reminder{
+		TaskID:  task.ID,
+		UserID:  userID,
+		Channel: model.ReminderChannelInApp,
+	}
+
+	if req.Channel != "" {
+		reminder.Channel = model.ReminderChannel(req.Channel)
+	}
+
+	if reminder.Channel == model.ReminderChannelWebhook {
+		if err := checkWebhookURL(req.WebhookURL); err != nil {
+			return nil, err
+		}
+		reminder.WebhookURL = req.WebhookURL
+	}
+
+	if hasRemindAt {
+		remindAt, err := time.Parse(time.RFC3339, *req.RemindAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remind_at format, use ISO 8601 (e.g., 2024-12-31T09:00:00Z)")
+		}
+		reminder.RemindAt = sql.NullTime{Time: remindAt.UTC(), Valid: true}
+		reminder.FireAt = reminder.RemindAt
+	} else {
+		if !task.DueDate.Valid {
+			return nil, fmt.Errorf("offset reminders need a task with a due_date")
+		}
+		offset := time.Duration(*req.OffsetMinutes) * time.Minute
+		reminder.OffsetMinutes = sql.NullInt64{Int64: int64(*req.OffsetMinutes), Valid: true}
+		reminder.FireAt = sql.NullTime{Time: task.DueDate.Time.Add(-offset), Valid: true}
+	}
+
+	if err := s.reminderRepo.Create(reminder); err != nil {
+		return nil, err
+	}
+
+	return toReminderResponse(reminder), nil
+}
+
+func (s *ReminderService) GetReminders(taskID, userID int) ([]dto.ReminderResponse, error) {
+	if _, err := s.task(taskID, userID); err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.reminderRepo.GetAllByTaskID(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ReminderResponse, len(reminders))
+	for i := range reminders {
+		responses[i] = *toReminderResponse(&reminders[i])
+	}
+
+	return responses, nil
+}
+
+func (s *ReminderService) DeleteReminder(reminderID, taskID, userID int) error {
+	return s.reminderRepo.Delete(reminderID, taskID, userID)
+}
+
+// task loads a task the user can see.
+func (s *ReminderService) task(taskID, userID int) (*model.Task, error) {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetByID(taskID, ownerID)
+}
+
+// checkWebhookURL only accepts absolute http(s) URLs whose host resolves to
+// public addresses. The webhook client checks the address again when it
+// connects, since DNS answers can change in between.
+func checkWebhookURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("webhook reminders need a webhook_url")
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Hostname() == "" {
+		return fmt.Errorf("webhook_url must be an http or https URL")
+	}
+
+	ips, err := net.LookupIP(parsed.Hostname())
+	if err != nil || len(ips) == 0 {
+		return fmt.Errorf("webhook_url host cannot be resolved")
+	}
+
+	for _, ip := range ips {
+		if !notifier.PublicAddress(ip) {
+			return fmt.Errorf("webhook_url must point to a public address")
+		}
+	}
+
+	return nil
+}
+
+func toReminderResponse(reminder *model.Reminder) *dto.ReminderResponse {
+	response := &dto.ReminderResponse{
+		ID:         reminder.ID,
+		TaskID:     reminder.TaskID,
+		Channel:    string(reminder.Channel),
+		WebhookURL: reminder.WebhookURL,
+		Attempts:   reminder.Attempts,
+		LastError:  reminder.LastError,
+		CreatedAt:  reminder.CreatedAt,
+	}
+
+	if reminder.RemindAt.Valid {
+		response.RemindAt = &reminder.RemindAt.Time
+	}
+
+	if reminder.OffsetMinutes.Valid {
+		offset := int(reminder.OffsetMinutes.Int64)
+		response.OffsetMinutes = &offset
+	}
+
+	if reminder.FireAt.Valid {
+		response.FireAt = &reminder.FireAt.Time
+	}
+
+	if reminder.SentAt.Valid {
+		response.SentAt = &reminder.SentAt.Time
+	}
+
+	if reminder.FailedAt.Valid {
+		response.FailedAt = &reminder.FailedAt.Time
+	}
+
+	return response
+}
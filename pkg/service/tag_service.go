@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+const defaultTagColor = "#808080"
+
+type TagService struct {
+	tagRepo *repository.TagRepository
+}
+
+func NewTagService(tagRepo *repository.TagRepository) *TagService {
+	return &TagService{tagRepo: tagRepo}
+}
+
+func (s *TagService) CreateTag(userID int, req *dto.CreateTagRequest) (*dto.TagResponse, error) {
+	color := req.Color
+	if color == "" {
+		color = defaultTagColor
+	}
+
+	tag := &model.Tag{
+		UserID: userID,
+		Name:   utils.SanitizeString(req.Name),
+		Color:  color,
+	}
+
+	if tag.Name == "" {
+		return nil, fmt.Errorf("tag name is required")
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, err
+	}
+
+	response := toTagResponse(tag)
+	return &response, nil
+}
+
+func (s *TagService) GetTags(userID int) ([]dto.TagResponse, error) {
+	tags, err := s.tagRepo.GetAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toTagResponses(tags), nil
+}
+
+func (s *TagService) UpdateTag(tagID, userID int, req *dto.UpdateTagRequest) (*dto.TagResponse, error) {
+	tag, err := s.tagRepo.GetByID(tagID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tag.Name = utils.SanitizeString(*req.Name)
+		if tag.Name == "" {
+			return nil, fmt.Errorf("tag name is required")
+		}
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+
+	response := toTagResponse(tag)
+	return &response, nil
+}
+
+func (s *TagService) DeleteTag(tagID, userID int) error {
+	return s.tagRepo.Delete(tagID, userID)
+}
+
+func toTagResponse(tag *model.Tag) dto.TagResponse {
+	return dto.TagResponse{
+		ID:    tag.ID,
+		Name:  tag.Name,
+		Color: tag.Color,
+	}
+}
+
+func toTagResponses(tags []model.Tag) []dto.TagResponse {
+	responses := make([]dto.TagResponse, len(tags))
+	for i := range tags {
+		responses[i] = toTagResponse(&tags[i])
+	}
+	return responses
+}
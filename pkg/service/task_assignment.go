@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/notifier"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+// AssignTask makes a user responsible for a task and notifies them. Only
+// users who can see the task, through its workspace or a share, can be
+// assigned. The creator of the task stays its owner.
+func (s *TaskService) AssignTask(taskID, userID int, req *dto.AssignTaskRequest) (*dto.TaskResponse, error) {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.GetByID(taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.AssigneeID.Valid && int(task.AssigneeID.Int64) == req.UserID {
+		return s.GetTask(taskID, userID)
+	}
+
+	if _, err := s.access.Task(taskID, req.UserID, model.RoleViewer); err != nil {
+		return nil, fmt.Errorf("the assignee does not have access to this task")
+	}
+
+	if err := s.setAssignee(task, userID, sql.NullInt64{Int64: int64(req.UserID), Valid: true}); err != nil {
+		return nil, err
+	}
+
+	if req.UserID != userID {
+		s.notifyAssignee(task, userID)
+	}
+
+	return s.GetTask(taskID, userID)
+}
+
+// UnassignTask removes the assignee of a task.
+func (s *TaskService) UnassignTask(taskID, userID int) (*dto.TaskResponse, error) {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.GetByID(taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.AssigneeID.Valid {
+		if err := s.setAssignee(task, userID, sql.NullInt64{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetTask(taskID, userID)
+}
+
+func (s *TaskService) setAssignee(task *model.Task, actorID int, assigneeID sql.NullInt64) error {
+	before := *task
+	task.AssigneeID = assigneeID
+
+	event := newTaskEvent(model.TaskEventAssigned, actorID, diffTasks(&before, task))
+	if err := s.taskRepo.Update(task, event); err != nil {
+		return fmt.Errorf("failed to assign task: %w", err)
+	}
+
+	return nil
+}
+
+// notifyAssignee tells the new assignee of a task who assigned it to them.
+// The assignment stands even when the notification cannot be delivered.
+func (s *TaskService) notifyAssignee(task *model.Task, actorID int) {
+	assigneeID := int(task.AssigneeID.Int64)
+
+	assignee, err := s.userRepo.GetByID(assigneeID)
+	if err != nil {
+		utils.Error("Failed to notify assignee of task %d: %v", task.ID, err)
+		return
+	}
+
+	actor, err := s.userRepo.GetByID(actorID)
+	if err != nil {
+		utils.Error("Failed to notify assignee of task %d: %v", task.ID, err)
+		return
+	}
+
+	err = s.notifier.Notify(context.Background(), &notifier.Message{
+		UserID:    assignee.ID,
+		Email:     assignee.Email,
+		Name:      assignee.Name,
+		Type:      model.NotificationTypeAssignment,
+		Title:     "Assigned to you: " + task.Title,
+		Body:      fmt.Sprintf("%s assigned the task \"%s\" to you.", actor.Name, task.Title),
+		TaskID:    task.ID,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		utils.Error("Failed to notify assignee of task %d: %v", task.ID, err)
+	}
+}
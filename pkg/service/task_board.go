@@ -0,0 +1,64 @@
+package service
+
+import (
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+)
+
+// boardTaskLimit is the number of tasks a board shows at most.
+const boardTaskLimit = 500
+
+// GetBoard returns the top-level tasks of a workspace grouped by status,
+// with a column for every status of the user in board order and the tasks
+// of each column in manual order. Tasks with a status of another member go
+// to the column with the same name, or else the first one of the same kind.
+func (s *TaskService) GetBoard(userID, workspaceID int, query *dto.BoardQuery) (*dto.BoardResponse, error) {
+	statuses, err := loadStatuses(s.statusRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &repository.TaskFilter{
+		WorkspaceID: workspaceID,
+		ProjectID:   query.ProjectID,
+		TopLevel:    true,
+		SortBy:      "position",
+		Order:       "asc",
+		Limit:       boardTaskLimit,
+	}
+
+	tasks, total, err := s.taskRepo.List(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	taskResponses, err := s.toTaskResponses(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]dto.BoardColumnResponse, len(statuses))
+	index := make(map[int]int, len(statuses))
+	for i := range statuses {
+		columns[i] = dto.BoardColumnResponse{
+			Status: *toTaskStatusResponse(&statuses[i]),
+			Tasks:  []dto.TaskResponse{},
+		}
+		index[statuses[i].ID] = i
+	}
+
+	for _, task := range taskResponses {
+		i, ok := index[task.StatusID]
+		if !ok {
+			// Tasks of other workspace members use their creator's
+			// statuses; show them in the viewer's matching column.
+			i = 0
+			if status := matchStatus(statuses, task.Status, task.IsCompleted); status != nil {
+				i = index[status.ID]
+			}
+		}
+		columns[i].Tasks = append(columns[i].Tasks, task)
+	}
+
+	return &dto.BoardResponse{Columns: columns, Total: total}, nil
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+)
+
+const (
+	BulkActionComplete      = "complete"
+	BulkActionUncomplete    = "uncomplete"
+	BulkActionDelete        = "delete"
+	BulkActionSetPriority   = "set_priority"
+	BulkActionSetDueDate    = "set_due_date"
+	BulkActionMoveToProject = "move_to_project"
+	BulkActionAddTag        = "add_tag"
+	BulkActionSetStatus     = "set_status"
+)
+
+// BulkUpdateTasks applies one action to many tasks in a single transaction.
+// Either every task is changed or none is. The tasks must be editable by
+// the user and belong to one workspace; they may have been created by
+// different members. The response lists the outcome for each task and is
+// returned together with the error when the operation failed because of
+// one of them.
+func (s *TaskService) BulkUpdateTasks(userID int, req *dto.BulkTaskRequest) (*dto.BulkTaskResponse, error) {
+	ids := uniqueIDs(req.TaskIDs)
+
+	owners, err := s.access.Tasks(ids, userID, model.RoleEditor)
+	if err != nil {
+		return bulkError(req.Action, ids, err)
+	}
+
+	var ownerIDs []int
+	seen := make(map[int]bool)
+	for _, id := range ids {
+		if !seen[owners[id]] {
+			seen[owners[id]] = true
+			ownerIDs = append(ownerIDs, owners[id])
+		}
+	}
+
+	op, target, err := s.bulkOperation(userID, ownerIDs, req)
+	if err != nil {
+		return nil, err
+	}
+	op.TaskIDs = ids
+
+	switch req.Action {
+	case BulkActionComplete, BulkActionUncomplete, BulkActionSetStatus:
+		target.statuses = make(map[int][]model.TaskStatus, len(ownerIDs))
+		for _, ownerID := range ownerIDs {
+			if target.statuses[ownerID], err = loadStatuses(s.statusRepo, ownerID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	switch req.Action {
+	case BulkActionDelete:
+		for _, id := range ids {
+			op.Events = append(op.Events, bulkEvent(id, newTaskEvent(model.TaskEventDeleted, userID, nil)))
+		}
+	case BulkActionAddTag:
+	default:
+		// The repository calls this with each task locked, so the change
+		// is made to its current state.
+		op.Apply = func(task *model.Task) (*repository.BulkTaskChange, error) {
+			return bulkChange(task, userID, req, target)
+		}
+	}
+
+	if err := s.taskRepo.BulkApply(op); err != nil {
+		return bulkError(req.Action, ids, err)
+	}
+
+	results := make([]dto.BulkTaskItemResult, len(ids))
+	for i, id := range ids {
+		results[i] = dto.BulkTaskItemResult{TaskID: id, Success: true}
+	}
+
+	return &dto.BulkTaskResponse{Action: req.Action, Applied: true, Results: results}, nil
+}
+
+// bulkChange applies a field-changing action to a task and returns what
+// has to be saved with it, or nil when the task is unchanged.
+func bulkChange(task *model.Task, userID int, req *dto.BulkTaskRequest, target *bulkTarget) (*repository.BulkTaskChange, error) {
+	before := *task
+	if err := applyBulkAction(task, req, target); err != nil {
+		return nil, err
+	}
+
+	change := &repository.BulkTaskChange{}
+	if task.IsCompleted && !before.IsCompleted && task.RecurrenceRule.Valid {
+		occurrence, err := nextOccurrence(task)
+		if err != nil {
+			return nil, err
+		}
+		if occurrence != nil {
+			task.RecurrenceRule = sql.NullString{}
+			if change.Next, err = newNextOccurrence(task, occurrence, target.statuses[task.UserID]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	changes := diffTasks(&before, task)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	action := model.TaskEventUpdated
+	if task.IsCompleted && !before.IsCompleted {
+		action = model.TaskEventCompleted
+	}
+	change.Event = newTaskEvent(action, userID, changes)
+	_, change.Reschedule = changes["due_date"]
+
+	return change, nil
+}
+
+// bulkTarget holds what a field-changing action sets on every task.
+// statuses are the statuses of each task owner; status and project are the
+// ones named by set_status and move_to_project.
+type bulkTarget struct {
+	statuses map[int][]model.TaskStatus
+	status   *model.TaskStatus
+	project  *model.Project
+}
+
+// bulkOperation validates the arguments of the action for tasks of ownerIDs
+// and prepares the parts of the operation that do not depend on the
+// individual tasks. The tag or status given must belong to the user or one
+// of the owners.
+func (s *TaskService) bulkOperation(userID int, ownerIDs []int, req *dto.BulkTaskRequest) (*repository.BulkTaskOperation, *bulkTarget, error) {
+	op := &repository.BulkTaskOperation{}
+	target := &bulkTarget{}
+	owners := append([]int{userID}, ownerIDs...)
+
+	switch req.Action {
+	case BulkActionDelete:
+		op.Trash = true
+	case BulkActionAddTag:
+		if req.TagID == 0 {
+			return nil, nil, fmt.Errorf("add_tag needs a tag_id")
+		}
+		found := false
+		for _, ownerID := range owners {
+			if _, err := s.checkTags([]int{req.TagID}, ownerID); err == nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("tag not found")
+		}
+		op.AddTagID = req.TagID
+	case BulkActionSetPriority:
+		if req.Priority == "" {
+			return nil, nil, fmt.Errorf("set_priority needs a priority")
+		}
+	case BulkActionSetDueDate:
+		if req.DueDate == nil {
+			return nil, nil, fmt.Errorf("set_due_date needs a due_date")
+		}
+		if _, err := parseBulkDueDate(*req.DueDate); err != nil {
+			return nil, nil, err
+		}
+	case BulkActionSetStatus:
+		if req.StatusID == 0 {
+			return nil, nil, fmt.Errorf("set_status needs a status_id")
+		}
+		for _, ownerID := range owners {
+			if status, err := s.statusRepo.GetByID(req.StatusID, ownerID); err == nil {
+				target.status = status
+				break
+			}
+		}
+		if target.status == nil {
+			return nil, nil, fmt.Errorf("status not found")
+		}
+	case BulkActionMoveToProject:
+		if req.ProjectID == nil {
+			return nil, nil, fmt.Errorf("move_to_project needs a project_id")
+		}
+		if *req.ProjectID != 0 {
+			project, err := s.checkProject(*req.ProjectID, userID)
+			if err != nil {
+				return nil, nil, err
+			}
+			target.project = project
+		}
+	}
+
+	return op, target, nil
+}
+
+// applyBulkAction changes a task according to a field-changing action.
+// Tasks of another owner than the target status get the owner's status with
+// the same name and kind; a project can only take tasks of its own owner
+// and workspace.
+func applyBulkAction(task *model.Task, req *dto.BulkTaskRequest, target *bulkTarget) error {
+	switch req.Action {
+	case BulkActionComplete, BulkActionUncomplete:
+		completed := req.Action == BulkActionComplete
+		if task.IsCompleted == completed {
+			break
+		}
+		status, err := statusForCompletion(target.statuses[task.UserID], completed)
+		if err != nil {
+			return err
+		}
+		setStatus(task, status)
+	case BulkActionSetStatus:
+		status := target.status
+		if status.UserID != task.UserID {
+			status = matchStatus(target.statuses[task.UserID], status.Name, status.IsDone)
+			if status == nil {
+				return fmt.Errorf("status not found")
+			}
+		}
+		setStatus(task, status)
+	case BulkActionSetPriority:
+		task.Priority = model.Priority(req.Priority)
+	case BulkActionSetDueDate:
+		dueDate, err := parseBulkDueDate(*req.DueDate)
+		if err != nil {
+			return err
+		}
+		if !dueDate.Valid && task.RecurrenceRule.Valid {
+			return fmt.Errorf("recurring tasks need a due_date")
+		}
+		task.DueDate = dueDate
+	case BulkActionMoveToProject:
+		if target.project != nil && target.project.WorkspaceID != task.WorkspaceID {
+			return fmt.Errorf("the task and the project belong to different workspaces")
+		}
+		if target.project != nil && target.project.UserID != task.UserID {
+			return fmt.Errorf("the task and the project belong to different users")
+		}
+		task.ProjectID = sql.NullInt64{Int64: int64(*req.ProjectID), Valid: *req.ProjectID != 0}
+	}
+
+	return nil
+}
+
+func parseBulkDueDate(value string) (sql.NullTime, error) {
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("invalid due_date format, use ISO 8601 (e.g., 2024-12-31T23:59:59Z)")
+	}
+
+	return sql.NullTime{Time: parsed.UTC(), Valid: true}, nil
+}
+
+func bulkEvent(taskID int, event *model.TaskEvent) *model.TaskEvent {
+	event.TaskID = taskID
+	return event
+}
+
+// bulkError reports a bulk operation that failed with err, naming the task
+// when err is a repository.TaskItemError.
+func bulkError(action string, ids []int, err error) (*dto.BulkTaskResponse, error) {
+	var itemErr *repository.TaskItemError
+	if errors.As(err, &itemErr) {
+		return bulkFailure(action, ids, itemErr.TaskID, itemErr.Err)
+	}
+
+	return nil, err
+}
+
+// bulkFailure reports a bulk operation that was not applied because of
+// failedID.
+func bulkFailure(action string, ids []int, failedID int, cause error) (*dto.BulkTaskResponse, error) {
+	results := make([]dto.BulkTaskItemResult, len(ids))
+	for i, id := range ids {
+		results[i] = dto.BulkTaskItemResult{TaskID: id, Error: "not applied because another task failed"}
+		if id == failedID {
+			results[i].Error = cause.Error()
+		}
+	}
+
+	return &dto.BulkTaskResponse{Action: action, Applied: false, Results: results},
+		fmt.Errorf("bulk %s failed for task %d: %w", action, failedID, cause)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+)
+
+// GetTaskHistory returns the events of a task, newest first. The history of
+// a task in the trash stays available until it is purged.
+func (s *TaskService) GetTaskHistory(taskID, userID int) ([]dto.TaskEventResponse, error) {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.taskRepo.GetByID(taskID, ownerID); err != nil {
+		if _, err := s.taskRepo.GetTrashedByID(taskID, ownerID); err != nil {
+			return nil, fmt.Errorf("task not found")
+		}
+	}
+
+	events, err := s.taskEventRepo.GetByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TaskEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = dto.TaskEventResponse{
+			ID:        event.ID,
+			TaskID:    event.TaskID,
+			Action:    event.Action,
+			Changes:   make(map[string]dto.FieldChangeResponse, len(event.Changes)),
+			CreatedAt: event.CreatedAt,
+		}
+
+		if event.ActorID.Valid {
+			actorID := int(event.ActorID.Int64)
+			responses[i].ActorID = &actorID
+		}
+
+		for field, change := range event.Changes {
+			responses[i].Changes[field] = dto.FieldChangeResponse{From: change.From, To: change.To}
+		}
+	}
+
+	return responses, nil
+}
+
+func newTaskEvent(action string, actorID int, changes map[string]model.FieldChange) *model.TaskEvent {
+	return &model.TaskEvent{
+		ActorID: sql.NullInt64{Int64: int64(actorID), Valid: actorID != 0},
+		Action:  action,
+		Changes: changes,
+	}
+}
+
+// createdChanges lists the fields a new task was created with.
+func createdChanges(task *model.Task) map[string]model.FieldChange {
+	changes := make(map[string]model.FieldChange)
+	for field, value := range taskFieldValues(task) {
+		if value != nil && value != "" && value != false {
+			changes[field] = model.FieldChange{From: nil, To: value}
+		}
+	}
+	return changes
+}
+
+// diffTasks returns the fields that differ between two versions of a task.
+func diffTasks(before, after *model.Task) map[string]model.FieldChange {
+	old := taskFieldValues(before)
+	changes := make(map[string]model.FieldChange)
+	for field, value := range taskFieldValues(after) {
+		if old[field] != value {
+			changes[field] = model.FieldChange{From: old[field], To: value}
+		}
+	}
+	return changes
+}
+
+// taskFieldValues returns the tracked fields of a task as comparable JSON
+// values, with nil for NULL.
+func taskFieldValues(task *model.Task) map[string]interface{} {
+	values := map[string]interface{}{
+		"title":               task.Title,
+		"description":         task.Description,
+		"is_completed":        task.IsCompleted,
+		"status_id":           task.StatusID,
+		"priority":            string(task.Priority),
+		"due_date":            nil,
+		"parent_id":           nil,
+		"project_id":          nil,
+		"assignee_id":         nil,
+		"recurrence_rule":     nil,
+		"recurrence_timezone": task.RecurrenceTimezone,
+	}
+
+	if task.DueDate.Valid {
+		values["due_date"] = task.DueDate.Time.UTC().Format(time.RFC3339)
+	}
+	if task.ParentID.Valid {
+		values["parent_id"] = task.ParentID.Int64
+	}
+	if task.ProjectID.Valid {
+		values["project_id"] = task.ProjectID.Int64
+	}
+	if task.AssigneeID.Valid {
+		values["assignee_id"] = task.AssigneeID.Int64
+	}
+	if task.RecurrenceRule.Valid {
+		values["recurrence_rule"] = task.RecurrenceRule.String
+	}
+
+	return values
+}
@@ -4,22 +4,64 @@ import (
 	"database/sql"
 	"fmt"
 	"time"
+	// Embedded so recurrence time zones work on hosts without zoneinfo.
+	_ "time/tzdata"
 
 	"github.com/faisal-amiruddin/YouDo/pkg/dto"
 	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/notifier"
 	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/rrule"
 	"github.com/faisal-amiruddin/YouDo/pkg/utils"
 )
 
+const (
+	defaultTaskListLimit = 50
+	maxTaskListLimit     = 100
+)
+
 type TaskService struct {
-	taskRepo *repository.TaskRepository
+	taskRepo       *repository.TaskRepository
+	tagRepo        *repository.TagRepository
+	projectRepo    *repository.ProjectRepository
+	taskEventRepo  *repository.TaskEventRepository
+	statusRepo     *repository.TaskStatusRepository
+	dependencyRepo *repository.TaskDependencyRepository
+	commentRepo    *repository.CommentRepository
+	userRepo       *repository.UserRepository
+	access         *AccessService
+	notifier       notifier.Notifier
 }
 
-func NewTaskService(taskRepo *repository.TaskRepository) *TaskService {
-	return &TaskService{taskRepo: taskRepo}
+func NewTaskService(
+	taskRepo *repository.TaskRepository,
+	tagRepo *repository.TagRepository,
+	projectRepo *repository.ProjectRepository,
+	taskEventRepo *repository.TaskEventRepository,
+	statusRepo *repository.TaskStatusRepository,
+	dependencyRepo *repository.TaskDependencyRepository,
+	commentRepo *repository.CommentRepository,
+	userRepo *repository.UserRepository,
+	access *AccessService,
+	taskNotifier notifier.Notifier,
+) *TaskService {
+	return &TaskService{
+		taskRepo:       taskRepo,
+		tagRepo:        tagRepo,
+		projectRepo:    projectRepo,
+		taskEventRepo:  taskEventRepo,
+		statusRepo:     statusRepo,
+		dependencyRepo: dependencyRepo,
+		commentRepo:    commentRepo,
+		userRepo:       userRepo,
+		access:         access,
+		notifier:       taskNotifier,
+	}
 }
 
-func (s *TaskService) CreateTask(userID int, req *dto.CreateTaskRequest) (*dto.TaskResponse, error) {
+// CreateTask adds a task to the workspace, or to the workspace of its parent
+// or project when one is given.
+func (s *TaskService) CreateTask(userID, workspaceID int, req *dto.CreateTaskRequest) (*dto.TaskResponse, error) {
 	priority := model.PriorityMedium
 	if req.Priority != "" {
 		priority = model.Priority(req.Priority)
@@ -31,68 +73,224 @@ func (s *TaskService) CreateTask(userID int, req *dto.CreateTaskRequest) (*dto.T
 		if err != nil {
 			return nil, fmt.Errorf("invalid due_date format, use ISO 8601 (e.g., 2024-12-31T23:59:59Z)")
 		}
-		dueDate = sql.NullTime{Time: parsed, Valid: true}
+		dueDate = sql.NullTime{Time: parsed.UTC(), Valid: true}
+	}
+
+	timezone, err := recurrenceTimezone(req.RecurrenceTimezone)
+	if err != nil {
+		return nil, err
 	}
 
 	task := &model.Task{
-		UserID:      userID,
-		Title:       utils.SanitizeString(req.Title),
-		Description: utils.SanitizeString(req.Description),
-		Priority:    priority,
-		DueDate:     dueDate,
+		UserID:             userID,
+		WorkspaceID:        workspaceID,
+		Title:              utils.SanitizeString(req.Title),
+		Description:        utils.SanitizeString(req.Description),
+		Priority:           priority,
+		DueDate:            dueDate,
+		RecurrenceTimezone: timezone,
+	}
+
+	if req.RecurrenceRule != "" {
+		rule, err := parseRecurrenceRule(req.RecurrenceRule, dueDate)
+		if err != nil {
+			return nil, err
+		}
+		task.RecurrenceRule = sql.NullString{String: rule, Valid: true}
+	}
+
+	// Tasks added to a shared parent or project belong to its owner.
+	if req.ParentID != nil {
+		parent, err := s.editableTask(*req.ParentID, userID)
+		if err != nil {
+			return nil, err
+		}
+		task.UserID, task.WorkspaceID = parent.UserID, parent.WorkspaceID
+		task.ParentID = sql.NullInt64{Int64: int64(*req.ParentID), Valid: true}
+	}
+	if req.ProjectID != nil {
+		project, err := s.checkProject(*req.ProjectID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if task.ParentID.Valid {
+			if project.UserID != task.UserID {
+				return nil, fmt.Errorf("the parent task and the project belong to different users")
+			}
+			if project.WorkspaceID != task.WorkspaceID {
+				return nil, fmt.Errorf("the parent task and the project belong to different workspaces")
+			}
+		}
+		task.UserID, task.WorkspaceID = project.UserID, project.WorkspaceID
+		task.ProjectID = sql.NullInt64{Int64: int64(*req.ProjectID), Valid: true}
 	}
 
-	if err := s.taskRepo.Create(task); err != nil {
+	statuses, err := loadStatuses(s.statusRepo, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := statusForCompletion(statuses, false)
+	if err != nil {
+		return nil, err
+	}
+	if req.StatusID != nil {
+		if status = findStatus(statuses, *req.StatusID); status == nil {
+			return nil, fmt.Errorf("status not found")
+		}
+	}
+	setStatus(task, status)
+
+	tagIDs, err := s.checkTags(req.TagIDs, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newTaskEvent(model.TaskEventCreated, userID, createdChanges(task))
+	if err := s.taskRepo.Create(task, event, tagIDs); err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
-	return s.toTaskResponse(task), nil
+	responses, err := s.toTaskResponses([]model.Task{*task})
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses[0], nil
+}
+
+func (s *TaskService) CreateSubtask(parentID, userID int, req *dto.CreateTaskRequest) (*dto.TaskResponse, error) {
+	req.ParentID = &parentID
+	return s.CreateTask(userID, 0, req)
 }
 
 func (s *TaskService) GetTask(taskID, userID int) (*dto.TaskResponse, error) {
-	task, err := s.taskRepo.GetByID(taskID, userID)
+	ownerID, err := s.access.Task(taskID, userID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.GetByID(taskID, ownerID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.toTaskResponse(task), nil
+	responses, err := s.toTaskResponses([]model.Task{*task})
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses[0], nil
 }
 
-func (s *TaskService) GetAllTasks(userID int) (*dto.TaskListResponse, error) {
-	tasks, err := s.taskRepo.GetAllByUserID(userID)
+func (s *TaskService) GetSubtasks(parentID, userID int) ([]dto.TaskResponse, error) {
+	ownerID, err := s.access.Task(parentID, userID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.taskRepo.GetByID(parentID, ownerID); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.GetSubtasks(parentID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toTaskResponses(tasks)
+}
+
+// GetAllTasks lists the tasks of a workspace together with the tasks shared
+// with the user.
+func (s *TaskService) GetAllTasks(userID, workspaceID int, query *dto.TaskListQuery) (*dto.TaskListResponse, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTaskListLimit
+	}
+	if limit > maxTaskListLimit {
+		limit = maxTaskListLimit
+	}
+
+	offset := query.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	filter := &repository.TaskFilter{
+		IsCompleted:   query.IsCompleted,
+		StatusID:      query.StatusID,
+		Actionable:    query.Actionable,
+		WorkspaceID:   workspaceID,
+		IncludeShared: true,
+		Priority:      model.Priority(query.Priority),
+		DueFrom:       query.DueFrom,
+		DueTo:         query.DueTo,
+		CreatedFrom:   query.CreatedFrom,
+		CreatedTo:     query.CreatedTo,
+		UpdatedFrom:   query.UpdatedFrom,
+		UpdatedTo:     query.UpdatedTo,
+		TagIDs:        uniqueIDs(query.Tags),
+		MatchAllTags:  query.TagMatch == "all",
+		SortBy:        query.SortBy,
+		Order:         query.Order,
+		Limit:         limit,
+		Offset:        offset,
+	}
+
+	switch query.Assignee {
+	case "me":
+		filter.AssigneeID = userID
+	case "unassigned":
+		filter.Unassigned = true
+	}
+
+	tasks, total, err := s.taskRepo.List(userID, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
-	taskResponses := make([]dto.TaskResponse, len(tasks))
-	for i, task := range tasks {
-		taskResponses[i] = *s.toTaskResponse(&task)
+	taskResponses, err := s.toTaskResponses(tasks)
+	if err != nil {
+		return nil, err
 	}
 
 	return &dto.TaskListResponse{
-		Tasks: taskResponses,
-		Total: len(taskResponses),
+		Tasks:  taskResponses,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
 	}, nil
 }
 
 func (s *TaskService) UpdateTask(taskID, userID int, req *dto.UpdateTaskRequest) (*dto.TaskResponse, error) {
-	task, err := s.taskRepo.GetByID(taskID, userID)
+	ownerID, err := s.access.Task(taskID, userID, model.RoleEditor)
 	if err != nil {
 		return nil, err
 	}
 
+	task, err := s.taskRepo.GetByID(taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	before := *task
+
 	if req.Title != nil {
 		task.Title = utils.SanitizeString(*req.Title)
 	}
 	if req.Description != nil {
 		task.Description = utils.SanitizeString(*req.Description)
 	}
-	if req.IsCompleted != nil {
-		task.IsCompleted = *req.IsCompleted
+	wasCompleted := task.IsCompleted
+	if req.StatusID != nil || req.IsCompleted != nil {
+		if err := s.updateStatus(task, req.StatusID, req.IsCompleted); err != nil {
+			return nil, err
+		}
 	}
 	if req.Priority != nil {
 		task.Priority = model.Priority(*req.Priority)
 	}
+	previousDueDate := task.DueDate
 	if req.DueDate != nil {
 		if *req.DueDate == "" {
 			task.DueDate = sql.NullTime{Valid: false}
@@ -101,29 +299,285 @@ func (s *TaskService) UpdateTask(taskID, userID int, req *dto.UpdateTaskRequest)
 			if err != nil {
 				return nil, fmt.Errorf("invalid due_date format, use ISO 8601 (e.g., 2024-12-31T23:59:59Z)")
 			}
-			task.DueDate = sql.NullTime{Time: parsed, Valid: true}
+			task.DueDate = sql.NullTime{Time: parsed.UTC(), Valid: true}
+		}
+	}
+	if req.ParentID != nil {
+		if *req.ParentID != 0 {
+			parent, err := s.editableTask(*req.ParentID, userID)
+			if err != nil {
+				return nil, err
+			}
+			if parent.UserID != ownerID {
+				return nil, fmt.Errorf("the task and its parent belong to different users")
+			}
+			if parent.WorkspaceID != task.WorkspaceID {
+				return nil, fmt.Errorf("the task and its parent belong to different workspaces")
+			}
 		}
+		task.ParentID = sql.NullInt64{Int64: int64(*req.ParentID), Valid: *req.ParentID != 0}
+	}
+	if req.ProjectID != nil && int64(*req.ProjectID) != task.ProjectID.Int64 {
+		if *req.ProjectID != 0 {
+			if err := s.checkTaskProject(*req.ProjectID, userID, ownerID, task.WorkspaceID); err != nil {
+				return nil, err
+			}
+		}
+		task.ProjectID = sql.NullInt64{Int64: int64(*req.ProjectID), Valid: *req.ProjectID != 0}
+	}
+
+	if req.RecurrenceTimezone != nil {
+		task.RecurrenceTimezone, err = recurrenceTimezone(*req.RecurrenceTimezone)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.RecurrenceRule != nil {
+		task.RecurrenceRule = sql.NullString{}
+		if *req.RecurrenceRule != "" {
+			rule, err := parseRecurrenceRule(*req.RecurrenceRule, task.DueDate)
+			if err != nil {
+				return nil, err
+			}
+			task.RecurrenceRule = sql.NullString{String: rule, Valid: true}
+		}
+	} else if task.RecurrenceRule.Valid && !task.DueDate.Valid {
+		return nil, fmt.Errorf("recurring tasks need a due_date")
+	}
+
+	var tagIDs []int
+	if req.TagIDs != nil {
+		tagIDs, err = s.checkTags(*req.TagIDs, ownerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var next *model.Task
+	if task.IsCompleted && !wasCompleted && task.RecurrenceRule.Valid {
+		next, err = nextOccurrence(task)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			// The series moves on to the next occurrence, so completing
+			// this task again does not repeat it a second time.
+			task.RecurrenceRule = sql.NullString{}
+		}
+	}
+
+	var event *model.TaskEvent
+	if changes := diffTasks(&before, task); len(changes) > 0 {
+		action := model.TaskEventUpdated
+		if task.IsCompleted && !wasCompleted {
+			action = model.TaskEventCompleted
+		}
+		event = newTaskEvent(action, userID, changes)
+	}
+
+	update := &repository.TaskUpdate{
+		Task:                task,
+		Event:               event,
+		TagIDs:              tagIDs,
+		SetTags:             req.TagIDs != nil,
+		RescheduleReminders: task.DueDate.Valid != previousDueDate.Valid || !task.DueDate.Time.Equal(previousDueDate.Time),
+		CompleteSubtasks:    task.IsCompleted && req.CompleteSubtasks,
+		ActorID:             userID,
 	}
 
-	if err := s.taskRepo.Update(task); err != nil {
+	if next != nil {
+		if update.Next, err = s.nextOccurrenceUpdate(task, next); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.taskRepo.Save(update); err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
 
-	return s.toTaskResponse(task), nil
+	tasks := []model.Task{*task}
+	if next != nil {
+		tasks = append(tasks, *next)
+	}
+
+	responses, err := s.toTaskResponses(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	if next != nil {
+		responses[0].NextOccurrence = &responses[1]
+	}
+
+	return &responses[0], nil
 }
 
+// MoveTask changes the place of a task in the manual order of its
+// workspace. The user must be able to edit the task and to see the target.
+func (s *TaskService) MoveTask(taskID, userID int, req *dto.MoveTaskRequest) (*dto.TaskResponse, error) {
+	if (req.BeforeID == nil) == (req.AfterID == nil) {
+		return nil, fmt.Errorf("exactly one of before_id or after_id is required")
+	}
+
+	targetID, after := 0, false
+	if req.BeforeID != nil {
+		targetID = *req.BeforeID
+	} else {
+		targetID, after = *req.AfterID, true
+	}
+
+	if targetID == taskID {
+		return nil, fmt.Errorf("a task cannot be moved next to itself")
+	}
+
+	if _, err := s.access.Task(taskID, userID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.access.Task(targetID, userID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.taskRepo.Move(taskID, targetID, after); err != nil {
+		return nil, err
+	}
+
+	return s.GetTask(taskID, userID)
+}
+
+// AddBlocker makes the task wait for blockerID to be completed. The user
+// must be able to edit the task and to see the blocker.
+func (s *TaskService) AddBlocker(taskID, userID int, req *dto.AddBlockerRequest) (*dto.TaskResponse, error) {
+	if req.BlockerID == taskID {
+		return nil, fmt.Errorf("a task cannot block itself")
+	}
+
+	if _, err := s.access.Task(taskID, userID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.access.Task(req.BlockerID, userID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	if err := s.dependencyRepo.Add(taskID, req.BlockerID); err != nil {
+		return nil, err
+	}
+
+	return s.GetTask(taskID, userID)
+}
+
+func (s *TaskService) RemoveBlocker(taskID, blockerID, userID int) (*dto.TaskResponse, error) {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.dependencyRepo.Remove(taskID, blockerID, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.GetTask(taskID, userID)
+}
+
+// DeleteTask moves a task and its subtasks to the trash.
 func (s *TaskService) DeleteTask(taskID, userID int) error {
-	return s.taskRepo.Delete(taskID, userID)
+	ownerID, err := s.access.Task(taskID, userID, model.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	return s.taskRepo.Delete(taskID, ownerID, newTaskEvent(model.TaskEventDeleted, userID, nil))
+}
+
+// GetTrash lists the trash of a workspace. Every member can see it, since
+// members can restore the tasks they could edit before.
+func (s *TaskService) GetTrash(workspaceID int, query *dto.TrashListQuery) (*dto.TaskListResponse, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTaskListLimit
+	}
+	if limit > maxTaskListLimit {
+		limit = maxTaskListLimit
+	}
+
+	offset := query.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	tasks, total, err := s.taskRepo.GetTrash(workspaceID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	taskResponses, err := s.toTaskResponses(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TaskListResponse{
+		Tasks:  taskResponses,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// RestoreTask takes a task out of the trash. Like deleting it, this needs
+// the editor role.
+func (s *TaskService) RestoreTask(taskID, userID int) (*dto.TaskResponse, error) {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.Restore(taskID, ownerID, newTaskEvent(model.TaskEventRestored, userID, nil)); err != nil {
+		return nil, err
+	}
+
+	return s.GetTask(taskID, userID)
+}
+
+// PurgeTask permanently deletes a task that is in the trash. Only its owner
+// and the admins of its workspace can do this.
+func (s *TaskService) PurgeTask(taskID, userID int) error {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	return s.taskRepo.Purge(taskID, ownerID)
+}
+
+// EmptyTrash permanently deletes the trashed tasks of a workspace that the
+// user may purge: all of them for admins and the owner, whose role on
+// workspace items is RoleOwner, and their own tasks for other members.
+func (s *TaskService) EmptyTrash(userID, workspaceID int, role model.WorkspaceRole) (*dto.PurgeTrashResponse, error) {
+	ownerID := userID
+	if role.ItemRole().Allows(model.RoleOwner) {
+		ownerID = 0
+	}
+
+	purged, err := s.taskRepo.PurgeTrash(workspaceID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PurgeTrashResponse{Purged: purged}, nil
 }
 
 func (s *TaskService) toTaskResponse(task *model.Task) *dto.TaskResponse {
 	response := &dto.TaskResponse{
 		ID:          task.ID,
 		UserID:      task.UserID,
+		WorkspaceID: task.WorkspaceID,
 		Title:       task.Title,
 		Description: task.Description,
 		IsCompleted: task.IsCompleted,
+		StatusID:    task.StatusID,
 		Priority:    string(task.Priority),
+		Position:    task.Position,
 		CreatedAt:   task.CreatedAt,
 		UpdatedAt:   task.UpdatedAt,
 	}
@@ -132,5 +586,291 @@ func (s *TaskService) toTaskResponse(task *model.Task) *dto.TaskResponse {
 		response.DueDate = &task.DueDate.Time
 	}
 
+	if task.ParentID.Valid {
+		parentID := int(task.ParentID.Int64)
+		response.ParentID = &parentID
+	}
+
+	if task.ProjectID.Valid {
+		projectID := int(task.ProjectID.Int64)
+		response.ProjectID = &projectID
+	}
+
+	if task.AssigneeID.Valid {
+		assigneeID := int(task.AssigneeID.Int64)
+		response.AssigneeID = &assigneeID
+	}
+
+	if task.DeletedAt.Valid {
+		response.DeletedAt = &task.DeletedAt.Time
+	}
+
+	if task.RecurrenceRule.Valid {
+		response.RecurrenceRule = task.RecurrenceRule.String
+		response.RecurrenceTimezone = task.RecurrenceTimezone
+	}
+
 	return response
-}
\ No newline at end of file
+}
+
+// toTaskResponses converts tasks and loads the subtask progress and tags of
+// all of them with one query each.
+func (s *TaskService) toTaskResponses(tasks []model.Task) ([]dto.TaskResponse, error) {
+	ids := make([]int, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+
+	progress, err := s.taskRepo.GetSubtaskProgress(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.GetByTaskIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	statusIDs := make([]int, len(tasks))
+	for i := range tasks {
+		statusIDs[i] = tasks[i].StatusID
+	}
+
+	statuses, err := s.statusRepo.GetByIDs(uniqueIDs(statusIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	blockers, err := s.dependencyRepo.GetBlockers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	commentCounts, err := s.commentRepo.CountByTaskIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TaskResponse, len(tasks))
+	for i := range tasks {
+		responses[i] = *s.toTaskResponse(&tasks[i])
+		responses[i].Status = statuses[tasks[i].StatusID].Name
+		responses[i].BlockedBy = []int{}
+		if b, ok := blockers[tasks[i].ID]; ok {
+			responses[i].BlockedBy = b.IDs
+			responses[i].IsBlocked = b.Open > 0
+		}
+		if p, ok := progress[tasks[i].ID]; ok {
+			responses[i].Subtasks = &dto.SubtaskProgressResponse{Done: p.Done, Total: p.Total}
+		}
+		responses[i].Tags = toTagResponses(tags[tasks[i].ID])
+		responses[i].CommentCount = commentCounts[tasks[i].ID]
+	}
+
+	return responses, nil
+}
+
+// checkTags removes duplicate tag IDs and makes sure all of them belong to
+// the user.
+func (s *TaskService) checkTags(tagIDs []int, userID int) ([]int, error) {
+	ids := uniqueIDs(tagIDs)
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	count, err := s.tagRepo.CountOwned(ids, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if count != len(ids) {
+		return nil, fmt.Errorf("tag not found")
+	}
+
+	return ids, nil
+}
+
+// updateStatus moves a task to statusID, or to the first done or open
+// status when only completed is given and differs from the task.
+func (s *TaskService) updateStatus(task *model.Task, statusID *int, completed *bool) error {
+	statuses, err := loadStatuses(s.statusRepo, task.UserID)
+	if err != nil {
+		return err
+	}
+
+	if statusID != nil {
+		status := findStatus(statuses, *statusID)
+		if status == nil {
+			return fmt.Errorf("status not found")
+		}
+		if completed != nil && *completed != status.IsDone {
+			return fmt.Errorf("is_completed does not match the status")
+		}
+		setStatus(task, status)
+		return nil
+	}
+
+	if *completed == task.IsCompleted {
+		return nil
+	}
+
+	status, err := statusForCompletion(statuses, *completed)
+	if err != nil {
+		return err
+	}
+	setStatus(task, status)
+
+	return nil
+}
+
+// editableTask loads a task the user may edit, such as the parent of a new
+// subtask.
+func (s *TaskService) editableTask(taskID, userID int) (*model.Task, error) {
+	ownerID, err := s.access.Task(taskID, userID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetByID(taskID, ownerID)
+}
+
+// checkProject makes sure the user can add tasks to the project and returns
+// it.
+func (s *TaskService) checkProject(projectID, userID int) (*model.Project, error) {
+	ownerID, err := s.access.Project(projectID, userID, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(projectID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.ArchivedAt.Valid {
+		return nil, fmt.Errorf("project is archived")
+	}
+
+	return project, nil
+}
+
+// checkTaskProject makes sure the user can move tasks of ownerID in
+// workspaceID to the project.
+func (s *TaskService) checkTaskProject(projectID, userID, ownerID, workspaceID int) error {
+	project, err := s.checkProject(projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	if project.UserID != ownerID {
+		return fmt.Errorf("the task and the project belong to different users")
+	}
+
+	if project.WorkspaceID != workspaceID {
+		return fmt.Errorf("the task and the project belong to different workspaces")
+	}
+
+	return nil
+}
+
+// nextOccurrenceUpdate prepares the next occurrence of a completed
+// recurring task, in the first open status of the owner, to be saved with
+// the task.
+func (s *TaskService) nextOccurrenceUpdate(task, next *model.Task) (*repository.NextOccurrence, error) {
+	statuses, err := loadStatuses(s.statusRepo, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newNextOccurrence(task, next, statuses)
+}
+
+// newNextOccurrence is nextOccurrenceUpdate with the owner's statuses
+// already loaded.
+func newNextOccurrence(task, next *model.Task, statuses []model.TaskStatus) (*repository.NextOccurrence, error) {
+	status, err := statusForCompletion(statuses, false)
+	if err != nil {
+		return nil, err
+	}
+	setStatus(next, status)
+
+	return &repository.NextOccurrence{
+		PreviousID: task.ID,
+		Task:       next,
+		Event:      newTaskEvent(model.TaskEventCreated, task.UserID, createdChanges(next)),
+	}, nil
+}
+
+// nextOccurrence returns a copy of a recurring task due at its next
+// occurrence, or nil when the series has ended. The due date is advanced in
+// the task's time zone so it keeps its local time across DST changes.
+func nextOccurrence(task *model.Task) (*model.Task, error) {
+	rule, err := rrule.Parse(task.RecurrenceRule.String)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence_rule: %w", err)
+	}
+
+	loc, err := time.LoadLocation(task.RecurrenceTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence_timezone")
+	}
+
+	due, nextRule, ok := rule.Next(task.DueDate.Time.In(loc))
+	if !ok {
+		return nil, nil
+	}
+
+	return &model.Task{
+		UserID:             task.UserID,
+		WorkspaceID:        task.WorkspaceID,
+		ParentID:           task.ParentID,
+		ProjectID:          task.ProjectID,
+		AssigneeID:         task.AssigneeID,
+		Title:              task.Title,
+		Description:        task.Description,
+		Priority:           task.Priority,
+		DueDate:            sql.NullTime{Time: due.UTC(), Valid: true},
+		RecurrenceRule:     sql.NullString{String: nextRule.String(), Valid: true},
+		RecurrenceTimezone: task.RecurrenceTimezone,
+	}, nil
+}
+
+// parseRecurrenceRule validates an RRULE value and returns it in canonical
+// form. Occurrences are counted from the due date, so one is required.
+func parseRecurrenceRule(value string, dueDate sql.NullTime) (string, error) {
+	rule, err := rrule.Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid recurrence_rule: %w", err)
+	}
+
+	if !dueDate.Valid {
+		return "", fmt.Errorf("recurring tasks need a due_date")
+	}
+
+	return rule.String(), nil
+}
+
+// recurrenceTimezone validates an IANA time zone name, defaulting to UTC.
+func recurrenceTimezone(name string) (string, error) {
+	if name == "" {
+		return "UTC", nil
+	}
+
+	if _, err := time.LoadLocation(name); err != nil {
+		return "", fmt.Errorf("invalid recurrence_timezone")
+	}
+
+	return name, nil
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+type TaskStatusService struct {
+	statusRepo *repository.TaskStatusRepository
+}
+
+func NewTaskStatusService(statusRepo *repository.TaskStatusRepository) *TaskStatusService {
+	return &TaskStatusService{statusRepo: statusRepo}
+}
+
+func (s *TaskStatusService) CreateStatus(userID int, req *dto.CreateTaskStatusRequest) (*dto.TaskStatusResponse, error) {
+	statuses, err := loadStatuses(s.statusRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &model.TaskStatus{
+		UserID: userID,
+		Name:   utils.SanitizeString(req.Name),
+		IsDone: req.IsDone,
+	}
+
+	if status.Name == "" {
+		return nil, fmt.Errorf("status name is required")
+	}
+
+	if req.Position != nil {
+		status.Position = *req.Position
+	} else if len(statuses) > 0 {
+		status.Position = statuses[len(statuses)-1].Position + 1
+	}
+
+	if err := s.statusRepo.Create(status); err != nil {
+		return nil, err
+	}
+
+	return toTaskStatusResponse(status), nil
+}
+
+func (s *TaskStatusService) GetStatuses(userID int) ([]dto.TaskStatusResponse, error) {
+	statuses, err := loadStatuses(s.statusRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toTaskStatusResponses(statuses), nil
+}
+
+// UpdateStatus renames or reorders a status. Changing its done flag also
+// completes or reopens every task in it, without creating next occurrences
+// of recurring tasks.
+func (s *TaskStatusService) UpdateStatus(statusID, userID int, req *dto.UpdateTaskStatusRequest) (*dto.TaskStatusResponse, error) {
+	status, err := s.statusRepo.GetByID(statusID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		status.Name = utils.SanitizeString(*req.Name)
+		if status.Name == "" {
+			return nil, fmt.Errorf("status name is required")
+		}
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	}
+	if req.IsDone != nil {
+		status.IsDone = *req.IsDone
+	}
+
+	if err := s.statusRepo.Update(status); err != nil {
+		return nil, err
+	}
+
+	return toTaskStatusResponse(status), nil
+}
+
+// DeleteStatus deletes a status after moving its tasks to query.MoveTo.
+func (s *TaskStatusService) DeleteStatus(statusID, userID int, query *dto.DeleteTaskStatusQuery) error {
+	if query.MoveTo != 0 {
+		if query.MoveTo == statusID {
+			return fmt.Errorf("tasks cannot be moved to the deleted status")
+		}
+		if _, err := s.statusRepo.GetByID(query.MoveTo, userID); err != nil {
+			return err
+		}
+	}
+
+	return s.statusRepo.Delete(statusID, userID, query.MoveTo)
+}
+
+// loadStatuses returns the statuses of a user in board order, giving them
+// the default ones the first time.
+func loadStatuses(statusRepo *repository.TaskStatusRepository, userID int) ([]model.TaskStatus, error) {
+	statuses, err := statusRepo.GetAllByUserID(userID)
+	if err != nil || len(statuses) > 0 {
+		return statuses, err
+	}
+
+	if err := statusRepo.EnsureDefaults(userID); err != nil {
+		return nil, err
+	}
+
+	return statusRepo.GetAllByUserID(userID)
+}
+
+// findStatus returns the status with the given ID, or nil.
+func findStatus(statuses []model.TaskStatus, id int) *model.TaskStatus {
+	for i := range statuses {
+		if statuses[i].ID == id {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// statusForCompletion returns the first status on the board that is done
+// or open as requested.
+func statusForCompletion(statuses []model.TaskStatus, completed bool) (*model.TaskStatus, error) {
+	for i := range statuses {
+		if statuses[i].IsDone == completed {
+			return &statuses[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no matching status found")
+}
+
+// matchStatus finds the status in statuses that corresponds to a status of
+// another user: one with the same name and kind, else the first of the same
+// kind. It returns nil when statuses has neither.
+func matchStatus(statuses []model.TaskStatus, name string, isDone bool) *model.TaskStatus {
+	for i := range statuses {
+		if statuses[i].IsDone == isDone && strings.EqualFold(statuses[i].Name, name) {
+			return &statuses[i]
+		}
+	}
+
+	status, err := statusForCompletion(statuses, isDone)
+	if err != nil {
+		return nil
+	}
+	return status
+}
+
+// setStatus moves a task to a status and keeps is_completed in line with it.
+func setStatus(task *model.Task, status *model.TaskStatus) {
+	task.StatusID = status.ID
+	task.IsCompleted = status.IsDone
+}
+
+func toTaskStatusResponse(status *model.TaskStatus) *dto.TaskStatusResponse {
+	return &dto.TaskStatusResponse{
+		ID:        status.ID,
+		Name:      status.Name,
+		Position:  status.Position,
+		IsDone:    status.IsDone,
+		CreatedAt: status.CreatedAt,
+		UpdatedAt: status.UpdatedAt,
+	}
+}
+
+func toTaskStatusResponses(statuses []model.TaskStatus) []dto.TaskStatusResponse {
+	responses := make([]dto.TaskStatusResponse, len(statuses))
+	for i := range statuses {
+		responses[i] = *toTaskStatusResponse(&statuses[i])
+	}
+	return responses
+}
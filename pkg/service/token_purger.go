@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+// TokenPurger deletes expired refresh tokens and access token denylist
+// entries, which would otherwise accumulate forever.
+type TokenPurger struct {
+	tokenRepo *repository.TokenRepository
+	interval  time.Duration
+}
+
+func NewTokenPurger(tokenRepo *repository.TokenRepository, interval time.Duration) *TokenPurger {
+	return &TokenPurger{
+		tokenRepo: tokenRepo,
+		interval:  interval,
+	}
+}
+
+// Run purges expired tokens every interval until ctx is cancelled.
+func (p *TokenPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.RunOnce(); err != nil {
+			utils.Error("Token purger: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce purges the expired tokens and returns the number of deleted rows.
+func (p *TokenPurger) RunOnce() (int, error) {
+	purged, err := p.tokenRepo.PurgeExpired(time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		utils.Info("Purged %d expired tokens", purged)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+// TrashPurger permanently deletes tasks that have been in the trash for
+// longer than the retention period.
+type TrashPurger struct {
+	taskRepo  *repository.TaskRepository
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewTrashPurger(taskRepo *repository.TaskRepository, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		taskRepo:  taskRepo,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run purges the trash every interval until ctx is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.RunOnce(); err != nil {
+			utils.Error("Trash purger: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce purges the expired trash and returns the number of deleted tasks.
+func (p *TrashPurger) RunOnce() (int, error) {
+	purged, err := p.taskRepo.PurgeDeletedBefore(time.Now().UTC().Add(-p.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		utils.Info("Purged %d tasks from the trash", purged)
+	}
+
+	return purged, nil
+}
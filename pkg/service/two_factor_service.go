@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+const (
+	totpIssuer        = "YouDo"
+	totpSkew          = 1
+	recoveryCodeCount = 10
+)
+
+type TwoFactorService struct {
+	userRepo      *repository.UserRepository
+	twoFactorRepo *repository.TwoFactorRepository
+}
+
+func NewTwoFactorService(userRepo *repository.UserRepository, twoFactorRepo *repository.TwoFactorRepository) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+// Enroll generates a new secret for the user. Two-factor auth stays off
+// until the secret is confirmed with a first code.
+func (s *TwoFactorService) Enroll(userID int) (*dto.TwoFactorEnrollResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.SetPendingSecret(user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor auth once the user proves their app works and
+// returns a fresh set of recovery codes.
+func (s *TwoFactorService) Confirm(userID int, code string) (*dto.RecoveryCodesResponse, error) {
+	secret, enabled, err := s.twoFactorRepo.GetSecret(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("two-factor enrollment has not been started")
+	}
+
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, fmt.Errorf("invalid two-factor code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.Enable(userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *TwoFactorService) Disable(userID int, req *dto.TwoFactorDisableRequest) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.CheckPassword(user.PasswordHash, req.Password); err != nil {
+		return fmt.Errorf("password is incorrect")
+	}
+
+	if err := s.VerifyCode(userID, req.Code); err != nil {
+		return err
+	}
+
+	return s.twoFactorRepo.Disable(userID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a
+// current code.
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID int, code string) (*dto.RecoveryCodesResponse, error) {
+	if err := s.VerifyCode(userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifyCode accepts either a current TOTP code or an unused recovery code.
+// Each TOTP code and each recovery code can only be used once.
+func (s *TwoFactorService) VerifyCode(userID int, code string) error {
+	secret, enabled, err := s.twoFactorRepo.GetSecret(userID)
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	if step, ok := utils.ValidateTOTP(secret, code, time.Now(), totpSkew); ok {
+		fresh, err := s.twoFactorRepo.MarkStepUsed(userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return fmt.Errorf("two-factor code has already been used")
+		}
+		return nil
+	}
+
+	used, err := s.twoFactorRepo.ConsumeRecoveryCode(userID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return fmt.Errorf("invalid two-factor code")
+	}
+
+	return nil
+}
+
+// generateRecoveryCodes returns the plaintext codes shown to the user once
+// and the hashes that are stored.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		raw, err := utils.GenerateRandomToken(8)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes[i] = raw[:8] + "-" + raw[8:]
+		hashes[i] = utils.HashToken(raw)
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+type UserService struct {
+	userRepo         *repository.UserRepository
+	tokenRepo        *repository.TokenRepository
+	authService      *AuthService
+	twoFactorService *TwoFactorService
+}
+
+func NewUserService(
+	userRepo *repository.UserRepository,
+	tokenRepo *repository.TokenRepository,
+	authService *AuthService,
+	twoFactorService *TwoFactorService,
+) *UserService {
+	return &UserService{
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		authService:      authService,
+		twoFactorService: twoFactorService,
+	}
+}
+
+func (s *UserService) GetProfile(userID int) (*dto.UserResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toUserResponse(user)
+	return &response, nil
+}
+
+// UpdateProfile changes the name, email and/or password of a user. Changing
+// the email or password requires the current password, or for accounts
+// without one a confirmation by confirmWithoutPassword, so that users who
+// signed up through a provider can set a password. A new email has to be
+// verified again.
+func (s *UserService) UpdateProfile(userID int, req *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	emailChanged := req.Email != nil && utils.SanitizeString(*req.Email) != user.Email
+
+	if emailChanged || req.Password != nil {
+		if user.PasswordHash == "" {
+			if err := s.confirmWithoutPassword(user, req.Code, req.ReauthToken); err != nil {
+				return nil, err
+			}
+		} else {
+			if req.CurrentPassword == "" {
+				return nil, fmt.Errorf("current_password is required to change email or password")
+			}
+			if err := utils.CheckPassword(user.PasswordHash, req.CurrentPassword); err != nil {
+				return nil, fmt.Errorf("current password is incorrect")
+			}
+		}
+	}
+
+	if req.Password != nil && !utils.ValidatePassword(*req.Password) {
+		return nil, fmt.Errorf("password must be at least 8 characters and contain letters and numbers")
+	}
+
+	if req.Name != nil {
+		user.Name = utils.SanitizeString(*req.Name)
+	}
+
+	if emailChanged {
+		email := utils.SanitizeString(*req.Email)
+		if !utils.ValidateEmail(email) {
+			return nil, fmt.Errorf("invalid email format")
+		}
+
+		exists, err := s.userRepo.EmailExists(email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check email: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("email already registered")
+		}
+
+		user.Email = email
+		user.EmailVerifiedAt = sql.NullTime{Valid: false}
+	}
+
+	hashedPassword := ""
+	if req.Password != nil {
+		hashedPassword, err = utils.HashPassword(*req.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+	}
+
+	if err := s.userRepo.Update(user, hashedPassword); err != nil {
+		return nil, err
+	}
+
+	if emailChanged {
+		if err := s.authService.SendVerificationEmail(user); err != nil {
+			utils.Error("Failed to send verification mail to user %d: %v", user.ID, err)
+		}
+	}
+
+	response := toUserResponse(user)
+	return &response, nil
+}
+
+// DeleteAccount removes the user together with all of their data and
+// revokes the access token used for the request. It is confirmed like a
+// password change in UpdateProfile.
+func (s *UserService) DeleteAccount(claims *utils.Claims, req *dto.DeleteAccountRequest) error {
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.PasswordHash == "" {
+		if err := s.confirmWithoutPassword(user, req.Code, req.ReauthToken); err != nil {
+			return err
+		}
+	} else if err := utils.CheckPassword(user.PasswordHash, req.Password); err != nil {
+		return fmt.Errorf("password is incorrect")
+	}
+
+	if err := s.userRepo.Delete(user.ID); err != nil {
+		return err
+	}
+
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		return s.tokenRepo.DenyAccessToken(claims.ID, claims.ExpiresAt.Time.UTC())
+	}
+
+	return nil
+}
+
+// confirmWithoutPassword confirms a sensitive change on an account that only
+// signs in through a provider, with a fresh sign-in at the provider or a
+// two-factor code. A bearer token alone is not enough.
+func (s *UserService) confirmWithoutPassword(user *model.User, code, reauthToken string) error {
+	if reauthToken != "" {
+		return s.authService.CheckReauthToken(reauthToken, user.ID)
+	}
+
+	if code != "" && user.TOTPEnabledAt.Valid {
+		return s.twoFactorService.VerifyCode(user.ID, code)
+	}
+
+	return fmt.Errorf("reauth_token or a two-factor code is required for accounts without a password")
+}
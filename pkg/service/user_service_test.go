@@ -0,0 +1,267 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/database/databasetest"
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+const testPassword = "password123"
+
+type userFixture struct {
+	db            *sql.DB
+	userRepo      *repository.UserRepository
+	workspaceRepo *repository.WorkspaceRepository
+	statusRepo    *repository.TaskStatusRepository
+}
+
+func newUserFixture(t *testing.T) *userFixture {
+	db := databasetest.Open(t)
+
+	return &userFixture{
+		db:            db,
+		userRepo:      repository.NewUserRepository(db),
+		workspaceRepo: repository.NewWorkspaceRepository(db),
+		statusRepo:    repository.NewTaskStatusRepository(db),
+	}
+}
+
+// createUser registers a user with a personal workspace and the default
+// statuses, like the first request of a new user does.
+func (f *userFixture) createUser(t *testing.T, email string) (userID, workspaceID int) {
+	t.Helper()
+
+	hash, err := utils.HashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &model.User{Email: email, Name: email, PasswordHash: hash}
+	if err := f.userRepo.Create(user); err != nil {
+		t.Fatal(err)
+	}
+
+	member, err := f.workspaceRepo.GetDefaultMembership(user.ID, "Personal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.statusRepo.EnsureDefaults(user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	return user.ID, member.WorkspaceID
+}
+
+func (f *userFixture) createTask(t *testing.T, userID, workspaceID int, title string) int {
+	t.Helper()
+
+	var id int
+	err := f.db.QueryRow(`
+		INSERT INTO tasks (user_id, workspace_id, title, status_id)
+		SELECT $1, $2, $3, id FROM task_statuses WHERE user_id = $1 ORDER BY position LIMIT 1
+		RETURNING id
+	`, userID, workspaceID, title).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	return id
+}
+
+func (f *userFixture) exec(t *testing.T, query string, args ...interface{}) {
+	t.Helper()
+
+	if _, err := f.db.Exec(query, args...); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+}
+
+func (f *userFixture) count(t *testing.T, query string, args ...interface{}) int {
+	t.Helper()
+
+	var n int
+	if err := f.db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+
+	return n
+}
+
+func TestDeleteAccountRemovesUserData(t *testing.T) {
+	f := newUserFixture(t)
+
+	aliceID, personalID := f.createUser(t, "alice@example.com")
+	bobID, bobPersonalID := f.createUser(t, "bob@example.com")
+
+	taskID := f.createTask(t, aliceID, personalID, "Personal task")
+	subtaskID := f.createTask(t, aliceID, personalID, "Subtask")
+	f.exec(t, `UPDATE tasks SET parent_id = $1 WHERE id = $2`, taskID, subtaskID)
+
+	var tagID, projectID int
+	if err := f.db.QueryRow(`INSERT INTO tags (user_id, name) VALUES ($1, 'home') RETURNING id`, aliceID).Scan(&tagID); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.db.QueryRow(
+		`INSERT INTO projects (user_id, workspace_id, name) VALUES ($1, $2, 'Chores') RETURNING id`, aliceID, personalID,
+	).Scan(&projectID); err != nil {
+		t.Fatal(err)
+	}
+	f.exec(t, `INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2)`, taskID, tagID)
+	f.exec(t, `UPDATE tasks SET project_id = $1 WHERE id = $2`, projectID, taskID)
+	f.exec(t, `INSERT INTO task_events (task_id, actor_id, action) VALUES ($1, $2, 'created')`, taskID, aliceID)
+	f.exec(t, `INSERT INTO task_reminders (task_id, user_id, offset_minutes) VALUES ($1, $2, 30)`, taskID, aliceID)
+	f.exec(t, `INSERT INTO notifications (user_id, type, title, task_id) VALUES ($1, 'reminder', 'Due soon', $2)`, aliceID, taskID)
+
+	// Tokens and sign-in methods.
+	f.exec(t, `INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at) VALUES ($1, 'refresh', 'family', NOW() + INTERVAL '1 day')`, aliceID)
+	f.exec(t, `INSERT INTO personal_access_tokens (user_id, name, token_hash, token_prefix) VALUES ($1, 'cli', 'pat', 'ydp_abcd')`, aliceID)
+	f.exec(t, `INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, 'reset', NOW() + INTERVAL '1 hour')`, aliceID)
+	f.exec(t, `INSERT INTO totp_recovery_codes (user_id, code_hash) VALUES ($1, 'recovery')`, aliceID)
+	f.exec(t, `INSERT INTO user_identities (user_id, provider, subject) VALUES ($1, 'google', 'alice')`, aliceID)
+
+	// Another user's data must survive.
+	f.createTask(t, bobID, bobPersonalID, "Bob's task")
+
+	users := NewUserService(f.userRepo, repository.NewTokenRepository(f.db), nil, nil)
+
+	err := users.DeleteAccount(&utils.Claims{UserID: aliceID}, &dto.DeleteAccountRequest{Password: "wrong password"})
+	if err == nil {
+		t.Fatal("DeleteAccount accepted a wrong password")
+	}
+
+	if err := users.DeleteAccount(&utils.Claims{UserID: aliceID}, &dto.DeleteAccountRequest{Password: testPassword}); err != nil {
+		t.Fatalf("DeleteAccount failed: %v", err)
+	}
+
+	ownedBy := []string{
+		"users WHERE id = $1",
+		"tasks WHERE user_id = $1",
+		"tags WHERE user_id = $1",
+		"projects WHERE user_id = $1",
+		"task_statuses WHERE user_id = $1",
+		"task_reminders WHERE user_id = $1",
+		"notifications WHERE user_id = $1",
+		"refresh_tokens WHERE user_id = $1",
+		"personal_access_tokens WHERE user_id = $1",
+		"password_reset_tokens WHERE user_id = $1",
+		"totp_recovery_codes WHERE user_id = $1",
+		"user_identities WHERE user_id = $1",
+		"workspace_members WHERE user_id = $1",
+		"task_events WHERE actor_id = $1",
+	}
+	for _, from := range ownedBy {
+		if n := f.count(t, `SELECT COUNT(*) FROM `+from, aliceID); n != 0 {
+			t.Errorf("%d rows left in %s", n, from)
+		}
+	}
+
+	personal := []string{
+		"workspaces WHERE id = $1",
+		"tasks WHERE workspace_id = $1",
+		"projects WHERE workspace_id = $1",
+	}
+	for _, from := range personal {
+		if n := f.count(t, `SELECT COUNT(*) FROM `+from, personalID); n != 0 {
+			t.Errorf("%d rows left in %s of the personal workspace", n, from)
+		}
+	}
+
+	if n := f.count(t, `SELECT COUNT(*) FROM tasks WHERE id IN ($1, $2)`, taskID, subtaskID); n != 0 {
+		t.Errorf("%d personal tasks left", n)
+	}
+	if n := f.count(t, `SELECT COUNT(*) FROM task_tags WHERE tag_id = $1`, tagID); n != 0 {
+		t.Errorf("%d task tags left", n)
+	}
+
+	if n := f.count(t, `SELECT COUNT(*) FROM users WHERE id = $1`, bobID); n != 1 {
+		t.Error("another user was deleted")
+	}
+	if n := f.count(t, `SELECT COUNT(*) FROM tasks WHERE user_id = $1`, bobID); n != 1 {
+		t.Error("the tasks of another user were touched")
+	}
+}
+
+func TestDeleteAccountKeepsSharedWorkspaceTasks(t *testing.T) {
+	f := newUserFixture(t)
+
+	aliceID, _ := f.createUser(t, "alice@example.com")
+	bobID, _ := f.createUser(t, "bob@example.com")
+
+	// Alice's task in Bob's workspace is handed to Bob, and her activity
+	// on Bob's task stays in its history.
+	team := &model.Workspace{Name: "Team"}
+	if err := f.workspaceRepo.Create(team, bobID); err != nil {
+		t.Fatal(err)
+	}
+	f.exec(t, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'admin')`, team.ID, aliceID)
+	sharedTaskID := f.createTask(t, aliceID, team.ID, "Shared task")
+	bobTaskID := f.createTask(t, bobID, team.ID, "Bob's task")
+	f.exec(t, `INSERT INTO task_events (task_id, actor_id, action) VALUES ($1, $2, 'updated')`, bobTaskID, aliceID)
+	f.exec(t, `INSERT INTO task_shares (task_id, user_id, role, shared_by) VALUES ($1, $2, 'editor', $3)`, bobTaskID, aliceID, bobID)
+
+	users := NewUserService(f.userRepo, repository.NewTokenRepository(f.db), nil, nil)
+	if err := users.DeleteAccount(&utils.Claims{UserID: aliceID}, &dto.DeleteAccountRequest{Password: testPassword}); err != nil {
+		t.Fatalf("DeleteAccount failed: %v", err)
+	}
+
+	if n := f.count(t, `SELECT COUNT(*) FROM tasks WHERE id = $1 AND user_id = $2 AND workspace_id = $3`, sharedTaskID, bobID, team.ID); n != 1 {
+		t.Error("the task in the shared workspace was not handed to its owner")
+	}
+	if n := f.count(t, `SELECT COUNT(*) FROM task_events WHERE task_id = $1 AND actor_id IS NULL`, bobTaskID); n != 1 {
+		t.Error("the event on the other user's task was not kept without its actor")
+	}
+	if n := f.count(t, `SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, team.ID, aliceID); n != 0 {
+		t.Error("the deleted user is still a member of the shared workspace")
+	}
+	if n := f.count(t, `SELECT COUNT(*) FROM task_shares WHERE user_id = $1`, aliceID); n != 0 {
+		t.Error("the deleted user still has task shares")
+	}
+	if n := f.count(t, `SELECT COUNT(*) FROM users WHERE id = $1`, bobID); n != 1 {
+		t.Error("the other user was deleted")
+	}
+}
+
+func TestDeleteAccountPassesOwnership(t *testing.T) {
+	f := newUserFixture(t)
+
+	aliceID, _ := f.createUser(t, "alice@example.com")
+	bobID, _ := f.createUser(t, "bob@example.com")
+	carolID, _ := f.createUser(t, "carol@example.com")
+
+	team := &model.Workspace{Name: "Team"}
+	if err := f.workspaceRepo.Create(team, aliceID); err != nil {
+		t.Fatal(err)
+	}
+	f.exec(t, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'member')`, team.ID, bobID)
+	f.exec(t, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, 'admin')`, team.ID, carolID)
+	taskID := f.createTask(t, aliceID, team.ID, "Team task")
+
+	users := NewUserService(f.userRepo, repository.NewTokenRepository(f.db), nil, nil)
+	if err := users.DeleteAccount(&utils.Claims{UserID: aliceID}, &dto.DeleteAccountRequest{Password: testPassword}); err != nil {
+		t.Fatalf("DeleteAccount failed: %v", err)
+	}
+
+	member, err := f.workspaceRepo.GetMembership(team.ID, carolID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member.Role != model.WorkspaceRoleOwner {
+		t.Errorf("admin role = %s, want owner", member.Role)
+	}
+
+	if n := f.count(t, `SELECT COUNT(*) FROM tasks WHERE id = $1 AND user_id = $2`, taskID, carolID); n != 1 {
+		t.Error("the team task was not handed to the new owner")
+	}
+	if n := f.count(t, `
+		SELECT COUNT(*) FROM tasks t JOIN task_statuses s ON s.id = t.status_id
+		WHERE t.id = $1 AND s.user_id = $2
+	`, taskID, carolID); n != 1 {
+		t.Error("the team task does not use a status of the new owner")
+	}
+}
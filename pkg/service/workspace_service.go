@@ -0,0 +1,376 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/faisal-amiruddin/YouDo/pkg/dto"
+	"github.com/faisal-amiruddin/YouDo/pkg/mailer"
+	"github.com/faisal-amiruddin/YouDo/pkg/model"
+	"github.com/faisal-amiruddin/YouDo/pkg/repository"
+	"github.com/faisal-amiruddin/YouDo/pkg/utils"
+)
+
+// personalWorkspaceName is the name of the workspace created for users who
+// are not a member of any workspace.
+const personalWorkspaceName = "Personal"
+
+// WorkspaceConfig holds the settings WorkspaceService needs from
+// config.Config.
+type WorkspaceConfig struct {
+	InvitationExpiry time.Duration
+	FrontendURL      string
+}
+
+// WorkspaceService manages workspaces, their members and invitations.
+// Admins manage the members and invitations of a workspace; only the owner
+// can appoint, change or remove admins and delete the workspace.
+type WorkspaceService struct {
+	workspaceRepo *repository.WorkspaceRepository
+	userRepo      *repository.UserRepository
+	mailer        mailer.Mailer
+	cfg           WorkspaceConfig
+}
+
+func NewWorkspaceService(
+	workspaceRepo *repository.WorkspaceRepository,
+	userRepo *repository.UserRepository,
+	mailSender mailer.Mailer,
+	cfg WorkspaceConfig,
+) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		mailer:        mailSender,
+		cfg:           cfg,
+	}
+}
+
+// ResolveWorkspace returns the membership of a user in a workspace, or in
+// their default workspace when workspaceID is zero.
+func (s *WorkspaceService) ResolveWorkspace(userID, workspaceID int) (*model.WorkspaceMember, error) {
+	if workspaceID == 0 {
+		return s.workspaceRepo.GetDefaultMembership(userID, personalWorkspaceName)
+	}
+
+	return s.workspaceRepo.GetMembership(workspaceID, userID)
+}
+
+func (s *WorkspaceService) CreateWorkspace(userID int, req *dto.CreateWorkspaceRequest) (*dto.WorkspaceResponse, error) {
+	workspace := &model.Workspace{Name: utils.SanitizeString(req.Name)}
+	if workspace.Name == "" {
+		return nil, fmt.Errorf("workspace name is required")
+	}
+
+	if err := s.workspaceRepo.Create(workspace, userID); err != nil {
+		return nil, err
+	}
+
+	return toWorkspaceResponse(workspace), nil
+}
+
+func (s *WorkspaceService) GetWorkspaces(userID int) ([]dto.WorkspaceResponse, error) {
+	workspaces, err := s.workspaceRepo.GetAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WorkspaceResponse, len(workspaces))
+	for i := range workspaces {
+		responses[i] = *toWorkspaceResponse(&workspaces[i])
+	}
+
+	return responses, nil
+}
+
+func (s *WorkspaceService) GetWorkspace(workspaceID, userID int) (*dto.WorkspaceResponse, error) {
+	workspace, err := s.workspaceRepo.GetByID(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toWorkspaceResponse(workspace), nil
+}
+
+func (s *WorkspaceService) UpdateWorkspace(workspaceID, userID int, req *dto.UpdateWorkspaceRequest) (*dto.WorkspaceResponse, error) {
+	if _, err := s.member(workspaceID, userID, model.WorkspaceRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.workspaceRepo.GetByID(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace.Name = utils.SanitizeString(req.Name)
+	if workspace.Name == "" {
+		return nil, fmt.Errorf("workspace name is required")
+	}
+
+	if err := s.workspaceRepo.Update(workspace); err != nil {
+		return nil, err
+	}
+
+	return toWorkspaceResponse(workspace), nil
+}
+
+// DeleteWorkspace deletes a workspace with all of its tasks and projects.
+func (s *WorkspaceService) DeleteWorkspace(workspaceID, userID int) error {
+	if _, err := s.member(workspaceID, userID, model.WorkspaceRoleOwner); err != nil {
+		return err
+	}
+
+	return s.workspaceRepo.Delete(workspaceID)
+}
+
+func (s *WorkspaceService) GetMembers(workspaceID, userID int) ([]dto.WorkspaceMemberResponse, error) {
+	if _, err := s.member(workspaceID, userID, model.WorkspaceRoleMember); err != nil {
+		return nil, err
+	}
+
+	members, err := s.workspaceRepo.GetMembers(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WorkspaceMemberResponse, len(members))
+	for i := range members {
+		responses[i] = *toWorkspaceMemberResponse(&members[i])
+	}
+
+	return responses, nil
+}
+
+func (s *WorkspaceService) UpdateMember(workspaceID, userID, memberID int, req *dto.UpdateWorkspaceMemberRequest) (*dto.WorkspaceMemberResponse, error) {
+	actor, err := s.member(workspaceID, userID, model.WorkspaceRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkManageable(workspaceID, actor, memberID); err != nil {
+		return nil, err
+	}
+
+	role := model.WorkspaceRole(req.Role)
+	if role == model.WorkspaceRoleAdmin && actor.Role != model.WorkspaceRoleOwner {
+		return nil, ErrForbidden
+	}
+
+	if err := s.workspaceRepo.UpdateMemberRole(workspaceID, memberID, role); err != nil {
+		return nil, err
+	}
+
+	members, err := s.workspaceRepo.GetMembers(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range members {
+		if members[i].UserID == memberID {
+			return toWorkspaceMemberResponse(&members[i]), nil
+		}
+	}
+
+	return nil, fmt.Errorf("member not found")
+}
+
+// RemoveMember removes a member from a workspace. Members other than the
+// owner can also leave on their own. The tasks and projects they created
+// stay in the workspace.
+func (s *WorkspaceService) RemoveMember(workspaceID, userID, memberID int) error {
+	required := model.WorkspaceRoleAdmin
+	if memberID == userID {
+		required = model.WorkspaceRoleMember
+	}
+
+	actor, err := s.member(workspaceID, userID, required)
+	if err != nil {
+		return err
+	}
+
+	if memberID == userID {
+		if actor.Role == model.WorkspaceRoleOwner {
+			return fmt.Errorf("the owner cannot leave the workspace")
+		}
+	} else if err := s.checkManageable(workspaceID, actor, memberID); err != nil {
+		return err
+	}
+
+	return s.workspaceRepo.RemoveMember(workspaceID, memberID)
+}
+
+// InviteMember e-mails an invitation to join a workspace. The address does
+// not need to belong to an account yet.
+func (s *WorkspaceService) InviteMember(workspaceID, userID int, req *dto.CreateInvitationRequest) (*dto.InvitationResponse, error) {
+	actor, err := s.member(workspaceID, userID, model.WorkspaceRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	role := model.WorkspaceRoleMember
+	if req.Role != "" {
+		role = model.WorkspaceRole(req.Role)
+	}
+
+	if role == model.WorkspaceRoleAdmin && actor.Role != model.WorkspaceRoleOwner {
+		return nil, ErrForbidden
+	}
+
+	email := utils.SanitizeString(req.Email)
+	if invitee, err := s.userRepo.GetByEmail(email); err == nil {
+		if _, err := s.workspaceRepo.GetMembership(workspaceID, invitee.ID); err == nil {
+			return nil, fmt.Errorf("user is already a member")
+		}
+	}
+
+	workspace, err := s.workspaceRepo.GetByID(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inviter, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &model.WorkspaceInvitation{
+		WorkspaceID: workspaceID,
+		Email:       email,
+		Role:        role,
+		TokenHash:   utils.HashToken(token),
+		InvitedBy:   sql.NullInt64{Int64: int64(userID), Valid: true},
+		ExpiresAt:   time.Now().UTC().Add(s.cfg.InvitationExpiry),
+	}
+
+	if err := s.workspaceRepo.CreateInvitation(invitation); err != nil {
+		return nil, err
+	}
+
+	link := fmt.Sprintf("%s/invitations/accept?token=%s", s.cfg.FrontendURL, url.QueryEscape(token))
+	msg := &mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("%s invited you to %s on YouDo", inviter.Name, workspace.Name),
+		Body: fmt.Sprintf(
+			"Hi,\n\n%s invited you to join the workspace %s on YouDo. Open the link below to accept, signing up first if you do not have an account yet:\n\n%s\n\nThe invitation expires in %s and can only be used by %s.\n",
+			inviter.Name, workspace.Name, link, s.cfg.InvitationExpiry, email,
+		),
+	}
+
+	if err := s.mailer.Send(msg); err != nil {
+		utils.Error("Failed to send invitation %d: %v", invitation.ID, err)
+	}
+
+	return toInvitationResponse(invitation), nil
+}
+
+func (s *WorkspaceService) GetInvitations(workspaceID, userID int) ([]dto.InvitationResponse, error) {
+	if _, err := s.member(workspaceID, userID, model.WorkspaceRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.workspaceRepo.GetPendingInvitations(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.InvitationResponse, len(invitations))
+	for i := range invitations {
+		responses[i] = *toInvitationResponse(&invitations[i])
+	}
+
+	return responses, nil
+}
+
+func (s *WorkspaceService) DeleteInvitation(workspaceID, invitationID, userID int) error {
+	if _, err := s.member(workspaceID, userID, model.WorkspaceRoleAdmin); err != nil {
+		return err
+	}
+
+	return s.workspaceRepo.DeleteInvitation(invitationID, workspaceID)
+}
+
+// AcceptInvitation adds the user to the workspace of an invitation sent to
+// their email address.
+func (s *WorkspaceService) AcceptInvitation(userID int, req *dto.AcceptInvitationRequest) (*dto.WorkspaceResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceID, err := s.workspaceRepo.AcceptInvitation(utils.HashToken(req.Token), userID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetWorkspace(workspaceID, userID)
+}
+
+// member checks that the user is a member of the workspace with at least
+// the required role.
+func (s *WorkspaceService) member(workspaceID, userID int, required model.WorkspaceRole) (*model.WorkspaceMember, error) {
+	member, err := s.workspaceRepo.GetMembership(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !member.Role.Allows(required) {
+		return nil, ErrForbidden
+	}
+
+	return member, nil
+}
+
+// checkManageable makes sure actor may change the membership of memberID:
+// nobody can change the owner and only the owner can change admins.
+func (s *WorkspaceService) checkManageable(workspaceID int, actor *model.WorkspaceMember, memberID int) error {
+	target, err := s.workspaceRepo.GetMembership(workspaceID, memberID)
+	if err != nil {
+		return fmt.Errorf("member not found")
+	}
+
+	switch {
+	case target.Role == model.WorkspaceRoleOwner:
+		return fmt.Errorf("the workspace owner cannot be changed")
+	case target.Role == model.WorkspaceRoleAdmin && actor.Role != model.WorkspaceRoleOwner:
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+func toWorkspaceResponse(workspace *model.Workspace) *dto.WorkspaceResponse {
+	return &dto.WorkspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      string(workspace.Role),
+		CreatedAt: workspace.CreatedAt,
+		UpdatedAt: workspace.UpdatedAt,
+	}
+}
+
+func toWorkspaceMemberResponse(member *model.WorkspaceMember) *dto.WorkspaceMemberResponse {
+	return &dto.WorkspaceMemberResponse{
+		UserID:   member.UserID,
+		Email:    member.Email,
+		Name:     member.Name,
+		Role:     string(member.Role),
+		JoinedAt: member.CreatedAt,
+	}
+}
+
+func toInvitationResponse(invitation *model.WorkspaceInvitation) *dto.InvitationResponse {
+	return &dto.InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      string(invitation.Role),
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
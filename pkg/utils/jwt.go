@@ -8,16 +8,24 @@ import (
 )
 
 type Claims struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
+	UserID        int    `json:"user_id"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID int, email string, secret string, expiry time.Duration) (string, error) {
+func GenerateToken(userID int, email string, emailVerified bool, secret string, expiry time.Duration) (string, error) {
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:        userID,
+		Email:         email,
+		EmailVerified: emailVerified,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
@@ -45,3 +53,72 @@ func ValidateToken(tokenString string, secret string) (*Claims, error) {
 
 	return nil, fmt.Errorf("Invalid token")
 }
+
+// ChallengeClaims identify a user who passed the password check but still
+// has to provide a second factor, or who has just signed in again to
+// confirm a sensitive change.
+type ChallengeClaims struct {
+	UserID int `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// challengeKey derives a separate signing key so that a challenge token can
+// never be accepted by ValidateToken as an access token.
+func challengeKey(secret string) []byte {
+	return []byte(secret + ":2fa-challenge")
+}
+
+func GenerateChallengeToken(userID int, secret string, expiry time.Duration) (string, error) {
+	return signUserClaims(userID, challengeKey(secret), expiry)
+}
+
+func ValidateChallengeToken(tokenString string, secret string) (*ChallengeClaims, error) {
+	return parseUserClaims(tokenString, challengeKey(secret))
+}
+
+// reauthKey derives the signing key of re-authentication tokens, which
+// prove a fresh sign-in at a provider and must not pass as access tokens or
+// login challenges.
+func reauthKey(secret string) []byte {
+	return []byte(secret + ":reauth")
+}
+
+func GenerateReauthToken(userID int, secret string, expiry time.Duration) (string, error) {
+	return signUserClaims(userID, reauthKey(secret), expiry)
+}
+
+func ValidateReauthToken(tokenString string, secret string) (*ChallengeClaims, error) {
+	return parseUserClaims(tokenString, reauthKey(secret))
+}
+
+func signUserClaims(userID int, key []byte, expiry time.Duration) (string, error) {
+	claims := ChallengeClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(key)
+}
+
+func parseUserClaims(tokenString string, key []byte) (*ChallengeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ChallengeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+		}
+		return key, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*ChallengeClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("Invalid token")
+}
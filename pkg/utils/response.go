@@ -28,6 +28,15 @@ func ErrorResponse(c *gin.Context, statusCode int, message string) {
 	})
 }
 
+// ErrorDataResponse reports an error together with details in data.
+func ErrorDataResponse(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error: message,
+		Data: data,
+	})
+}
+
 func ValidationErrorResponse(c *gin.Context, errors interface{}) {
 	c.JSON(http.StatusBadRequest, Response{
 		Success: false,
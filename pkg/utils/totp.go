@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters used by authenticator apps. They are also written into
+// the otpauth:// URI so apps do not fall back to other defaults.
+const (
+	TOTPPeriod = 30
+	TOTPDigits = 6
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random 160 bit secret encoded as base32, the
+// form expected by authenticator apps.
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// DecodeTOTPSecret decodes a base32 secret, ignoring case, spaces and padding.
+func DecodeTOTPSecret(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	normalized = strings.TrimRight(normalized, "=")
+
+	key, err := totpEncoding.DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("invalid totp secret: %w", err)
+	}
+
+	return key, nil
+}
+
+// TOTPStep returns the RFC 6238 time step counter for t.
+func TOTPStep(t time.Time, period int64) int64 {
+	return t.Unix() / period
+}
+
+// HOTP computes an RFC 4226 one-time password for the given counter using
+// the hash function returned by newHash (sha1.New in the common case).
+func HOTP(key []byte, counter int64, digits int, newHash func() hash.Hash) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(newHash, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, code%mod)
+}
+
+// TOTPHash maps the otpauth algorithm names to hash constructors.
+func TOTPHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case "", "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported totp algorithm: %s", algorithm)
+	}
+}
+
+// GenerateTOTP computes the RFC 6238 code for time t.
+func GenerateTOTP(key []byte, t time.Time, period int64, digits int, newHash func() hash.Hash) string {
+	return HOTP(key, TOTPStep(t, period), digits, newHash)
+}
+
+// ValidateTOTP checks a six digit SHA-1 code against the secret, allowing
+// for skew steps of clock drift in either direction. It returns the
+// matching time step so callers can reject replays of the same code.
+func ValidateTOTP(secret, code string, t time.Time, skew int64) (int64, bool) {
+	key, err := DecodeTOTPSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(t, TOTPPeriod)
+	for step := current - skew; step <= current+skew; step++ {
+		expected := HOTP(key, step, TOTPDigits, sha1.New)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// TOTPURI builds the otpauth:// key URI understood by authenticator apps.
+func TOTPURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(TOTPDigits))
+	params.Set("period", fmt.Sprint(TOTPPeriod))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"testing"
+	"time"
+)
+
+// Seeds of the RFC 6238 Appendix B test vectors.
+var (
+	rfc6238SeedSHA1   = []byte("12345678901234567890")
+	rfc6238SeedSHA256 = []byte("12345678901234567890123456789012")
+	rfc6238SeedSHA512 = []byte("1234567890123456789012345678901234567890123456789012345678901234")
+)
+
+func TestGenerateTOTPRFC6238(t *testing.T) {
+	tests := []struct {
+		unix    int64
+		key     []byte
+		newHash func() hash.Hash
+		want    string
+	}{
+		{59, rfc6238SeedSHA1, sha1.New, "94287082"},
+		{1111111109, rfc6238SeedSHA1, sha1.New, "07081804"},
+		{1111111111, rfc6238SeedSHA1, sha1.New, "14050471"},
+		{1234567890, rfc6238SeedSHA1, sha1.New, "89005924"},
+		{2000000000, rfc6238SeedSHA1, sha1.New, "69279037"},
+		{20000000000, rfc6238SeedSHA1, sha1.New, "65353130"},
+		{59, rfc6238SeedSHA256, sha256.New, "46119246"},
+		{20000000000, rfc6238SeedSHA256, sha256.New, "77737706"},
+		{59, rfc6238SeedSHA512, sha512.New, "90693936"},
+		{20000000000, rfc6238SeedSHA512, sha512.New, "47863826"},
+	}
+
+	for _, tt := range tests {
+		got := GenerateTOTP(tt.key, time.Unix(tt.unix, 0), TOTPPeriod, 8, tt.newHash)
+		if got != tt.want {
+			t.Errorf("GenerateTOTP(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTPRFC6238(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238SeedSHA1)
+
+	// The six digit codes are the last six digits of the eight digit
+	// vectors.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(secret, tt.code, time.Unix(tt.unix, 0), 0)
+		if !ok {
+			t.Errorf("ValidateTOTP(%s at %d) rejected a valid code", tt.code, tt.unix)
+			continue
+		}
+		if want := tt.unix / TOTPPeriod; step != want {
+			t.Errorf("ValidateTOTP(%s at %d) step = %d, want %d", tt.code, tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238SeedSHA1)
+	now := time.Unix(1111111109, 0)
+	current := TOTPStep(now, TOTPPeriod)
+
+	codeAt := func(step int64) string {
+		return HOTP(rfc6238SeedSHA1, step, TOTPDigits, sha1.New)
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		skew     int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", secret, codeAt(current), 1, current, true},
+		{"previous step within skew", secret, codeAt(current - 1), 1, current - 1, true},
+		{"next step within skew", secret, codeAt(current + 1), 1, current + 1, true},
+		{"previous step without skew", secret, codeAt(current - 1), 0, 0, false},
+		{"next step without skew", secret, codeAt(current + 1), 0, 0, false},
+		{"two steps back", secret, codeAt(current - 2), 1, 0, false},
+		{"two steps ahead", secret, codeAt(current + 2), 1, 0, false},
+		{"two steps back with wider skew", secret, codeAt(current - 2), 2, current - 2, true},
+		{"surrounding whitespace", secret, " " + codeAt(current) + "\n", 1, current, true},
+		{"lower case secret with spaces", "gezd gnbv gy3t qojq gezd gnbv gy3t qojq", codeAt(current), 0, current, true},
+		{"too short", secret, codeAt(current)[:5], 1, 0, false},
+		{"eight digits", secret, HOTP(rfc6238SeedSHA1, current, 8, sha1.New), 1, 0, false},
+		{"wrong code", secret, "000000", 1, 0, false},
+		{"invalid secret", "not base32!", codeAt(current), 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(tt.secret, tt.code, now, tt.skew)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}